@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/kweonminsung/console-cinema/pkg/tui/player"
+	"github.com/spf13/cobra"
+)
+
+var castCmd = &cobra.Command{
+	Use:   "cast",
+	Short: "Play asciicast recordings",
+	Long:  `A container for commands that work with asciicast v2 recordings made with --record.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var castPlayCmd = &cobra.Command{
+	Use:   "play [file]",
+	Short: "Play an asciicast v2 recording",
+	Long: `Replay an asciicast v2 recording in the terminal, such as one written by 'play --record' or 'render --record'.
+Recordings made by other tools are supported as long as they only use common terminal sequences.`,
+	Example:       `  console-cinema cast play session.cast`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		castPlayer, err := player.NewCastPlayer(args[0])
+		if err != nil {
+			return err
+		}
+		return castPlayer.Play()
+	},
+}
+
+func init() {
+	castCmd.AddCommand(castPlayCmd)
+	rootCmd.AddCommand(castCmd)
+}
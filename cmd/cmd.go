@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/kweonminsung/console-cinema/pkg/media"
 	"github.com/spf13/cobra"
 )
 
@@ -15,11 +17,17 @@ var rootCmd = &cobra.Command{
 Commands:
   play     Play local video files (MP4, AVI, etc.)
   youtube  Play YouTube videos by URL
+  render   Write rendered frames to stdout
+  export   Export converted video to GIF, APNG or MP4
+  cast     Play asciicast recordings
   config   Manage configuration settings
 
 Examples:
   console-cinema play video.mp4 --mode ascii --fps 30
   console-cinema youtube https://youtube.com/watch?v=... --mode pixel
+  console-cinema render video.mp4 --format plain --frames 10
+  console-cinema export video.mp4 -o clip.gif --fps 12
+  console-cinema cast play session.cast
   console-cinema config show`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Console Cinema - Real-time ASCII/Pixel art video player")
@@ -38,6 +46,11 @@ func Execute() {
 	}
 }
 
+// modeUsage builds the --mode flag description from the registered renderers
+func modeUsage() string {
+	return fmt.Sprintf("Player mode (%s)", strings.Join(media.RendererNames(), ", "))
+}
+
 func init() {
 	// Add all subcommands to root
 	rootCmd.AddCommand(playCmd)
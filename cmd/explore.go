@@ -125,16 +125,13 @@ func updateVideoList(list *tview.List, videos []youtube.Video, app *tview.Applic
 
 func playVideo(url string) {
 	// This logic is borrowed from cmd/youtube.go
-	fps, _ := youtubeCmd.Flags().GetInt("fps")
-	loop, _ := youtubeCmd.Flags().GetBool("loop")
-	color, _ := youtubeCmd.Flags().GetBool("color")
-	mode, _ := youtubeCmd.Flags().GetString("mode")
+	config := playerConfigFromFlags(youtubeCmd.PersistentFlags(), url)
 
-	fmt.Printf("Starting %s player for YouTube video: %s\n", mode, url)
-	fmt.Printf("Settings - FPS: %d, Loop: %t, Color: %t, Mode: %s\n", fps, loop, color, mode)
+	fmt.Printf("Starting %s player for YouTube video: %s\n", config.Mode, url)
+	fmt.Printf("Settings - FPS: %d, Loop: %s, Color: %t, Mode: %s\n", config.FPS, config.Loop, config.Color, config.Mode)
 
 	// Create and start TUI player
-	player := player.NewPlayer(url, fps, loop, color, mode)
+	player := player.NewPlayer(config)
 
 	err := player.Play()
 	if err != nil {
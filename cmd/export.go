@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kweonminsung/console-cinema/pkg/export"
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+	"github.com/kweonminsung/console-cinema/pkg/video"
+	"github.com/kweonminsung/console-cinema/pkg/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file|url]",
+	Short: "Export converted video to GIF, APNG or MP4",
+	Long: `Convert a video and encode the frames as they would appear in a terminal, so they can be shared.
+Characters are drawn with a bundled monospace bitmap font and block, braille and sextant characters as shapes, so pixel mode becomes blocks of color.
+GIF and APNG are written with pure Go encoders; MP4 goes through OpenCV's VideoWriter.`,
+	Example: `  console-cinema export video.mp4 -o clip.gif --mode ascii --columns 100 --fps 12
+  console-cinema export video.mp4 -o clip.png --start 1:30 --end 1:40 --font-size 16
+  console-cinema export video.mp4 -o clip.mp4 --mode halfblock --colors 256`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		config := playerConfigFromFlags(flags, args[0])
+		if config.Mode == visualizer.ModeName {
+			return fmt.Errorf("%s mode only works in the player", visualizer.ModeName)
+		}
+
+		output, _ := flags.GetString("output")
+		if output == "" {
+			return fmt.Errorf("--output is required")
+		}
+		formatName, _ := flags.GetString("format")
+		var format export.Format
+		var err error
+		if formatName != "" {
+			format, err = export.ParseFormat(formatName)
+		} else {
+			format, err = export.FormatFromPath(output)
+		}
+		if err != nil {
+			return err
+		}
+
+		startFlag, _ := flags.GetString("start")
+		start, err := utils.ParseTimestamp(startFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --start: %v", err)
+		}
+		endFlag, _ := flags.GetString("end")
+		end, err := utils.ParseTimestamp(endFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --end: %v", err)
+		}
+		frames, _ := flags.GetInt("frames")
+		fps, _ := flags.GetFloat64("fps")
+		fontSize, _ := flags.GetInt("font-size")
+		config.Width, _ = flags.GetInt("columns")
+		config.Height, _ = flags.GetInt("rows")
+		if config.Width <= 0 {
+			return fmt.Errorf("--columns must be positive, got %d", config.Width)
+		}
+
+		var quantizer *media.Quantizer
+		depth, err := media.ParseColorDepth(config.ColorDepth)
+		if err != nil {
+			return err
+		}
+		if depth != media.ColorDepthAuto && depth != media.ColorDepthTrueColor {
+			quantizer = media.NewQuantizer(depth, config.ColorDither)
+		}
+
+		videoPlayer, err := video.NewVideoPlayer(config.Source, config)
+		if err != nil {
+			return fmt.Errorf("failed to create %s player: %v", config.Mode, err)
+		}
+		defer videoPlayer.Close()
+
+		if config.Height <= 0 {
+			videoPlayer.UpdateSize(config.Width, aspectHeight(videoPlayer, config.Width))
+		}
+
+		written, err := export.Export(videoPlayer, export.Options{
+			Format:    format,
+			Path:      output,
+			FPS:       fps,
+			Start:     start,
+			End:       end,
+			Frames:    frames,
+			FontSize:  fontSize,
+			Dither:    config.ColorDither,
+			Quantizer: quantizer,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d frames to %s\n", written, output)
+		return nil
+	},
+}
+
+func init() {
+	flags := exportCmd.Flags()
+	addRenderFlags(flags)
+	flags.StringP("output", "o", "", "Output file (.gif, .png/.apng or .mp4)")
+	flags.String("format", "", exportFormatUsage())
+	flags.Int("columns", 80, "Width of each frame in cells")
+	flags.Int("rows", 0, "Maximum height of each frame in cells (0 = follow the aspect ratio of the video)")
+	flags.Int("font-size", media.DefaultFontSize, "Height of a cell in pixels; the width follows the bundled font")
+	flags.Float64("fps", 0, "Output frame rate (0 = the frame rate of the video)")
+	flags.Int("frames", 0, "Maximum number of frames to export (0 = all)")
+	flags.String("start", "", "Position of the first frame, as seconds, [hh:]mm:ss or a duration like 1m30s")
+	flags.String("end", "", "Position to stop at, in the same forms as --start (default: the end of the video)")
+
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kweonminsung/console-cinema/pkg/audio"
+	"github.com/kweonminsung/console-cinema/pkg/export"
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+	"github.com/kweonminsung/console-cinema/pkg/video"
+	"github.com/kweonminsung/console-cinema/pkg/visualizer"
+	"github.com/spf13/pflag"
+)
+
+// addPlayerFlags registers the playback flags shared by the play and youtube commands
+func addPlayerFlags(flags *pflag.FlagSet) {
+	flags.IntP("fps", "f", 30, "Frames per second for playback")
+	flags.Float64("speed", 1.0, "Playback speed, from 0.25 to 4 (audio keeps its pitch)")
+	flags.BoolP("loop", "l", false, "Loop the video from the beginning (same as --loop-mode restart)")
+	flags.String("loop-mode", string(video.LoopOff), loopUsage())
+	flags.Int("color-tolerance", 0, "Skip redrawing cells whose colors changed by at most this much per channel (0-255)")
+	flags.String("record", "", "Record the terminal output to an asciicast v2 file")
+	flags.Int("volume", 100, fmt.Sprintf("Audio volume in percent, from 0 to %d (+/- change it while playing, m mutes)", audio.MaxVolume))
+	flags.String("audio-out", audio.OutputSpeaker, fmt.Sprintf("Where audio goes: %s, %s (discard it) or a .wav file to write it to", audio.OutputSpeaker, audio.OutputNull))
+	flags.String("audio-file", "", "Play this audio file instead of the audio of the video (WAV, FLAC, Ogg Vorbis, MP3 and raw s16le PCM are decoded without ffmpeg)")
+	flags.String("visualizer", string(visualizer.StyleSpectrum), visualizerUsage())
+	flags.Int("visualizer-strip", 0, "Rows under the video that show the audio visualizer (0 = off)")
+
+	addRenderFlags(flags)
+}
+
+// addRenderFlags registers the flags that control how frames are decoded and
+// converted, shared by every command that renders video
+func addRenderFlags(flags *pflag.FlagSet) {
+	flags.BoolP("color", "c", true, "Enable colored output")
+	flags.StringP("mode", "m", "pixel", modeUsage())
+	flags.String("colors", string(media.ColorDepthAuto), colorsUsage())
+	flags.Bool("color-dither", false, "Dither colors when quantizing to a limited palette")
+	flags.String("seek", string(media.SeekAccurate), seekUsage())
+	flags.Int("prefetch", video.DefaultPrefetchDepth, "Number of frames decoded and converted ahead of playback")
+	flags.Int("render-workers", 0, "Number of frames converted concurrently (0 = automatic)")
+
+	// ASCII mode
+	flags.String("charset", "standard", charsetUsage())
+	flags.Bool("invert", false, "Invert the character ramp for light-background terminals")
+	flags.Bool("calibrate", false, "Order the charset by the measured ink coverage of each glyph")
+
+	// Line mode
+	flags.Float64("line-threshold", media.DefaultLineThreshold, "Minimum gradient magnitude drawn as an edge (line mode)")
+	flags.String("line-charset", media.LineCharset, "Characters for vertical, diagonal, horizontal and anti-diagonal edges (line mode)")
+	flags.Bool("line-fill", false, "Fill non-edge areas with ASCII shading (line mode)")
+
+	// Braille mode
+	flags.String("dither", string(media.DitherFloydSteinberg), ditherUsage())
+
+	// Block modes
+	flags.Bool("sextant-fallback", false, "Use 2x2 quadrant glyphs in sextant mode when the terminal font lacks sextant glyphs")
+}
+
+// colorsUsage builds the --colors flag description from the supported color depths
+func colorsUsage() string {
+	depths := make([]string, len(media.ColorDepths))
+	for i, depth := range media.ColorDepths {
+		depths[i] = string(depth)
+	}
+	return fmt.Sprintf("Terminal color depth (%s)", strings.Join(depths, ", "))
+}
+
+// charsetUsage builds the --charset flag description from the charset presets
+func charsetUsage() string {
+	return fmt.Sprintf("ASCII charset preset (%s) or a custom string ordered from dark to bright",
+		strings.Join(media.CharsetPresetNames(), ", "))
+}
+
+// ditherUsage builds the --dither flag description from the supported dither modes
+func ditherUsage() string {
+	modes := make([]string, len(media.DitherModes))
+	for i, mode := range media.DitherModes {
+		modes[i] = string(mode)
+	}
+	return fmt.Sprintf("Dithering used by braille mode (%s)", strings.Join(modes, ", "))
+}
+
+// loopUsage builds the --loop-mode flag description from the supported loop modes
+func loopUsage() string {
+	modes := make([]string, len(video.LoopModes))
+	for i, mode := range video.LoopModes {
+		modes[i] = string(mode)
+	}
+	return fmt.Sprintf("Loop mode (%s); overrides --loop", strings.Join(modes, ", "))
+}
+
+// seekUsage builds the --seek flag description from the supported seek modes
+func seekUsage() string {
+	modes := make([]string, len(media.SeekModes))
+	for i, mode := range media.SeekModes {
+		modes[i] = string(mode)
+	}
+	return fmt.Sprintf("Seek mode (%s); accurate decodes from the previous keyframe to the exact frame", strings.Join(modes, ", "))
+}
+
+// visualizerUsage builds the --visualizer flag description from the visualizer styles
+func visualizerUsage() string {
+	styles := make([]string, len(visualizer.Styles))
+	for i, style := range visualizer.Styles {
+		styles[i] = string(style)
+	}
+	return fmt.Sprintf("Audio visualizer style (%s), shown by --mode %s and --visualizer-strip", strings.Join(styles, ", "), visualizer.ModeName)
+}
+
+// formatUsage builds the --format flag description from the supported frame formats
+func formatUsage() string {
+	formats := make([]string, len(media.FrameFormats))
+	for i, format := range media.FrameFormats {
+		formats[i] = string(format)
+	}
+	return fmt.Sprintf("Output format (%s)", strings.Join(formats, ", "))
+}
+
+// exportFormatUsage builds the --format flag description of the export command
+func exportFormatUsage() string {
+	formats := make([]string, len(export.Formats))
+	for i, format := range export.Formats {
+		formats[i] = string(format)
+	}
+	return fmt.Sprintf("Export format (%s); taken from the output extension by default", strings.Join(formats, ", "))
+}
+
+// playerConfigFromFlags reads the playback flags into a PlayerConfig for the given source.
+// Flags the command does not register are left at their zero values.
+func playerConfigFromFlags(flags *pflag.FlagSet, source string) types.PlayerConfig {
+	fps, _ := flags.GetInt("fps")
+	speed, _ := flags.GetFloat64("speed")
+	loop, _ := flags.GetString("loop-mode")
+	if restart, _ := flags.GetBool("loop"); restart && !flags.Changed("loop-mode") {
+		loop = string(video.LoopRestart)
+	}
+	color, _ := flags.GetBool("color")
+	mode, _ := flags.GetString("mode")
+	colors, _ := flags.GetString("colors")
+	colorDither, _ := flags.GetBool("color-dither")
+	colorTolerance, _ := flags.GetInt("color-tolerance")
+	seek, _ := flags.GetString("seek")
+	prefetch, _ := flags.GetInt("prefetch")
+	renderWorkers, _ := flags.GetInt("render-workers")
+	charset, _ := flags.GetString("charset")
+	invert, _ := flags.GetBool("invert")
+	calibrate, _ := flags.GetBool("calibrate")
+	lineThreshold, _ := flags.GetFloat64("line-threshold")
+	lineCharset, _ := flags.GetString("line-charset")
+	lineFill, _ := flags.GetBool("line-fill")
+	dither, _ := flags.GetString("dither")
+	sextantFallback, _ := flags.GetBool("sextant-fallback")
+	record, _ := flags.GetString("record")
+	audioFile, _ := flags.GetString("audio-file")
+	volume, _ := flags.GetInt("volume")
+	audioOut, _ := flags.GetString("audio-out")
+	visualizerStyle, _ := flags.GetString("visualizer")
+	visualizerStrip, _ := flags.GetInt("visualizer-strip")
+
+	return types.PlayerConfig{
+		Mode:             mode,
+		Color:            color,
+		FPS:              fps,
+		Speed:            speed,
+		Loop:             loop,
+		Source:           source,
+		IsYouTube:        utils.IsValidYouTubeURL(source),
+		ColorDepth:       colors,
+		ColorDither:      colorDither,
+		ColorTolerance:   colorTolerance,
+		SeekMode:         seek,
+		PrefetchDepth:    prefetch,
+		RenderWorkers:    renderWorkers,
+		Charset:          charset,
+		InvertCharset:    invert,
+		CalibrateCharset: calibrate,
+		LineThreshold:    lineThreshold,
+		LineCharset:      lineCharset,
+		LineFill:         lineFill,
+		Dither:           dither,
+		SextantFallback:  sextantFallback,
+		Record:           record,
+		AudioFile:        audioFile,
+		Volume:           volume,
+		AudioOut:         audioOut,
+		Visualizer:       visualizerStyle,
+		VisualizerStrip:  visualizerStrip,
+	}
+}
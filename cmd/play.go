@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/kweonminsung/console-cinema/pkg/audio"
 	"github.com/kweonminsung/console-cinema/pkg/tui/player"
 	"github.com/spf13/cobra"
 )
@@ -27,7 +28,7 @@ func isYouTubeURL(url string) bool {
 var playCmd = &cobra.Command{
 	Use:   "play [file]",
 	Short: "Play ASCII/Pixel animations from a local video file",
-	Long:  `Play ASCII/Pixel animations from a specified local video file (MP4, AVI, etc.). The video will be converted to ASCII art or pixel art in real-time and displayed in the terminal. Supports options for mode, FPS and looping.`,
+	Long:  `Play ASCII/Pixel animations from a specified local video file (MP4, AVI, etc.). The video will be converted to ASCII art or pixel art in real-time and displayed in the terminal. Files without a video stream, such as MP3 or FLAC songs, play with an audio-only view. Supports options for mode, FPS and looping.`,
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var filename string
@@ -47,16 +48,20 @@ var playCmd = &cobra.Command{
 			return
 		}
 
-		fps, _ := cmd.Flags().GetInt("fps")
-		loop, _ := cmd.Flags().GetBool("loop")
-		color, _ := cmd.Flags().GetBool("color")
-		mode, _ := cmd.Flags().GetString("mode")
+		config := playerConfigFromFlags(cmd.Flags(), filename)
 
-		fmt.Printf("Starting %s player for local file: %s\n", mode, filename)
-		fmt.Printf("Settings - FPS: %d, Loop: %t, Color: %t, Mode: %s\n", fps, loop, color, mode)
+		fmt.Printf("Starting %s player for local file: %s\n", config.Mode, filename)
+		fmt.Printf("Settings - FPS: %d, Loop: %s, Color: %t, Mode: %s\n", config.FPS, config.Loop, config.Color, config.Mode)
+		audioSource := filename
+		if config.AudioFile != "" {
+			audioSource = config.AudioFile
+		}
+		if err := audio.CheckSource(audioSource); err != nil {
+			fmt.Printf("Audio: disabled, %v\n", err)
+		}
 
 		// Create and start TUI player
-		player := player.NewPlayer(filename, fps, loop, color, mode)
+		player := player.NewPlayer(config)
 
 		err := player.Play()
 		if err != nil {
@@ -68,10 +73,7 @@ var playCmd = &cobra.Command{
 }
 
 func init() {
-	playCmd.Flags().BoolP("color", "c", true, "Enable colored output")
-	playCmd.Flags().IntP("fps", "f", 30, "Frames per second for playback")
-	playCmd.Flags().BoolP("loop", "l", false, "Loop the animation")
-	playCmd.Flags().StringP("mode", "m", "pixel", "Player mode (ascii, pixel)")
+	addPlayerFlags(playCmd.Flags())
 
 	rootCmd.AddCommand(playCmd)
 }
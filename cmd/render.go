@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kweonminsung/console-cinema/pkg/cast"
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+	"github.com/kweonminsung/console-cinema/pkg/video"
+	"github.com/kweonminsung/console-cinema/pkg/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render [file|url]",
+	Short: "Write rendered frames to stdout",
+	Long: `Convert a video and write its frames to stdout as plain text, ANSI escape sequences or the markup format, without taking over the terminal.
+Frames are written as fast as they are decoded, each followed by an empty line, so the output can be piped into other tools or captured in CI logs.
+With --colors auto, ANSI output uses 24-bit color.`,
+	Example: `  console-cinema render video.mp4 --mode ascii --format plain --frames 10
+  console-cinema render video.mp4 --start 00:01:30 --end 00:01:35 --width 120 > clip.ans`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		config := playerConfigFromFlags(flags, args[0])
+		if config.Mode == visualizer.ModeName {
+			return fmt.Errorf("%s mode only works in the player", visualizer.ModeName)
+		}
+
+		formatName, _ := flags.GetString("format")
+		format, err := media.ParseFrameFormat(formatName)
+		if err != nil {
+			return err
+		}
+		startFlag, _ := flags.GetString("start")
+		start, err := utils.ParseTimestamp(startFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --start: %v", err)
+		}
+		endFlag, _ := flags.GetString("end")
+		end, err := utils.ParseTimestamp(endFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --end: %v", err)
+		}
+		frames, _ := flags.GetInt("frames")
+		record, _ := flags.GetString("record")
+		config.Width, _ = flags.GetInt("width")
+		config.Height, _ = flags.GetInt("height")
+		if config.Width <= 0 {
+			return fmt.Errorf("--width must be positive, got %d", config.Width)
+		}
+
+		var quantizer *media.Quantizer
+		depth, err := media.ParseColorDepth(config.ColorDepth)
+		if err != nil {
+			return err
+		}
+		if (format == media.FormatANSI || record != "") && depth != media.ColorDepthAuto && depth != media.ColorDepthTrueColor {
+			quantizer = media.NewQuantizer(depth, config.ColorDither)
+		}
+
+		videoPlayer, err := video.NewVideoPlayer(config.Source, config)
+		if err != nil {
+			return fmt.Errorf("failed to create %s player: %v", config.Mode, err)
+		}
+		defer videoPlayer.Close()
+
+		if config.Height <= 0 {
+			videoPlayer.UpdateSize(config.Width, aspectHeight(videoPlayer, config.Width))
+		}
+
+		options := video.RenderOptions{
+			Start:     start,
+			End:       end,
+			Frames:    frames,
+			Quantizer: quantizer,
+		}
+
+		if record != "" {
+			recorder, err := cast.Create(record, config.Source)
+			if err != nil {
+				return err
+			}
+			if _, err := videoPlayer.Render(recorder, options); err != nil {
+				recorder.Close()
+				return err
+			}
+			return recorder.Close()
+		}
+
+		sink := video.NewTextSink(os.Stdout, format)
+		if _, err := videoPlayer.Render(sink, options); err != nil {
+			return err
+		}
+		return sink.Flush()
+	},
+}
+
+// aspectHeight returns the number of rows that keeps the aspect ratio of the
+// video at the given width, for output that has no terminal to fill
+func aspectHeight(videoPlayer *video.VideoPlayer, width int) int {
+	videoWidth, videoHeight := videoPlayer.GetVideoWidth(), videoPlayer.GetVideoHeight()
+	if videoWidth <= 0 || videoHeight <= 0 {
+		return width
+	}
+	return max(int(float64(width)*float64(videoHeight)/float64(videoWidth)*types.YScaleFactor+0.5), 1)
+}
+
+func init() {
+	flags := renderCmd.Flags()
+	addRenderFlags(flags)
+	flags.String("format", string(media.FormatANSI), formatUsage())
+	flags.Int("frames", 0, "Maximum number of frames to write (0 = all)")
+	flags.String("start", "", "Position of the first frame, as seconds, [hh:]mm:ss or a duration like 1m30s")
+	flags.String("end", "", "Position to stop at, in the same forms as --start (default: the end of the video)")
+	flags.Int("width", 80, "Width of each frame in cells")
+	flags.String("record", "", "Record the frames to an asciicast v2 file, timed by their timestamps, instead of writing to stdout")
+	flags.Int("height", 0, "Maximum height of each frame in cells (0 = follow the aspect ratio of the video)")
+
+	rootCmd.AddCommand(renderCmd)
+}
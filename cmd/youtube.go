@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/kweonminsung/console-cinema/pkg/audio"
 	"github.com/kweonminsung/console-cinema/pkg/tui/player"
 	"github.com/spf13/cobra"
 )
@@ -43,16 +44,20 @@ var youtubePlayCmd = &cobra.Command{
 		}
 
 		// Note: Flags are inherited from the parent youtubeCmd
-		fps, _ := cmd.Flags().GetInt("fps")
-		loop, _ := cmd.Flags().GetBool("loop")
-		color, _ := cmd.Flags().GetBool("color")
-		mode, _ := cmd.Flags().GetString("mode")
+		config := playerConfigFromFlags(cmd.Flags(), youtubeURL)
 
-		fmt.Printf("Starting %s player for YouTube video: %s\n", mode, youtubeURL)
-		fmt.Printf("Settings - FPS: %d, Loop: %t, Color: %t, Mode: %s\n", fps, loop, color, mode)
+		fmt.Printf("Starting %s player for YouTube video: %s\n", config.Mode, youtubeURL)
+		fmt.Printf("Settings - FPS: %d, Loop: %s, Color: %t, Mode: %s\n", config.FPS, config.Loop, config.Color, config.Mode)
+		audioSource := youtubeURL
+		if config.AudioFile != "" {
+			audioSource = config.AudioFile
+		}
+		if err := audio.CheckSource(audioSource); err != nil {
+			fmt.Printf("Audio: disabled, %v\n", err)
+		}
 
 		// Create and start TUI player
-		player := player.NewPlayer(youtubeURL, fps, loop, color, mode)
+		player := player.NewPlayer(config)
 
 		err := player.Play()
 		if err != nil {
@@ -85,8 +90,5 @@ func init() {
 	youtubeCmd.AddCommand(youtubePlayCmd)
 
 	// Flags for both play and explore (via playVideo)
-	youtubeCmd.PersistentFlags().BoolP("color", "c", true, "Enable colored output")
-	youtubeCmd.PersistentFlags().IntP("fps", "f", 30, "Frames per second for playback")
-	youtubeCmd.PersistentFlags().BoolP("loop", "l", false, "Loop the animation")
-	youtubeCmd.PersistentFlags().StringP("mode", "m", "pixel", "Player mode (ascii, pixel)")
+	addPlayerFlags(youtubeCmd.PersistentFlags())
 }
@@ -3,189 +3,289 @@ package audio
 import (
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/faiface/beep"
-	"github.com/faiface/beep/mp3"
-	"github.com/faiface/beep/speaker"
+	"github.com/faiface/beep/effects"
 	ytdlp "github.com/kweonminsung/console-cinema/third_party/yt-dlp"
 )
 
+// speakerBufferDuration is the amount of audio queued in the speaker ahead of what is heard
+const speakerBufferDuration = time.Second / 10
+
+// MaxVolume is the loudest volume in percent; 100 plays the audio unchanged
+const MaxVolume = 200
+
 // AudioPlayer manages audio playback
 type AudioPlayer struct {
-	ctrl      *beep.Ctrl
-	streamer  beep.StreamSeeker
-	format    beep.Format
-	closer    io.Closer
-	audioPath string
-	resampler *beep.Resampler
-	mutex     sync.Mutex
-	speed     float64
+	ctrl     *beep.Ctrl
+	streamer beep.StreamSeeker
+	format   beep.Format
+	codec    Codec
+	// source is the file or stream URL being played
+	source  string
+	closer  io.Closer
+	stretch *TimeStretch
+	tap     *Tap
+	volume  *effects.Volume
+	percent int
+	muted   bool
+	output  Output
+	mutex   sync.Mutex
+	speed   float64
 }
 
-// NewAudioPlayer creates a new AudioPlayer
-func NewAudioPlayer(videoPath string, isYouTube bool) (*AudioPlayer, error) {
-	audioPath := fmt.Sprintf("temp_audio_%d.mp3", time.Now().UnixNano())
-	var err error
+// NewAudioPlayer creates a new AudioPlayer for a video or a separate audio
+// file. Audio files are decoded in Go when their codec is supported, and
+// anything else is decoded by ffmpeg and piped in as PCM, so nothing is
+// written to disk. It returns ErrFFmpegNotFound or ErrNoAudioTrack when
+// there is no audio to play. A nil output plays through the speaker.
+func NewAudioPlayer(source string, isYouTube bool, output Output) (*AudioPlayer, error) {
+	if output == nil {
+		output = &speakerOutput{}
+	}
 
 	if isYouTube {
-		err = extractAudioFromYouTube(videoPath, audioPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to extract audio from YouTube: %v", err)
+		if err := CheckAvailable(); err != nil {
+			return nil, err
 		}
-	} else {
-		err = extractAudio(videoPath, audioPath)
+		streamURL, err := youTubeAudioURL(source)
 		if err != nil {
-			return nil, fmt.Errorf("failed to extract audio: %v", err)
+			return nil, fmt.Errorf("failed to get YouTube audio stream: %v", err)
 		}
+		source = streamURL
 	}
 
-	f, err := os.Open(audioPath)
+	streamer, format, codec, err := Open(source)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open audio file: %v", err)
+		return nil, err
 	}
 
-	streamer, format, err := mp3.Decode(f)
-	if err != nil {
-		f.Close()
-		return nil, fmt.Errorf("failed to decode mp3: %v", err)
-	}
-
-	resampler := beep.Resample(4, format.SampleRate, format.SampleRate, streamer)
-	ctrl := &beep.Ctrl{Streamer: resampler, Paused: false}
+	stretch := NewTimeStretch(streamer, format.SampleRate, 1.0)
+	// The tap sees the audio at the playback speed, before the volume is applied
+	tap := NewTap(stretch)
+	volume := &effects.Volume{Streamer: tap, Base: 2}
+	ctrl := &beep.Ctrl{Streamer: volume, Paused: false}
 
 	return &AudioPlayer{
-		ctrl:      ctrl,
-		streamer:  streamer,
-		format:    format,
-		closer:    f,
-		audioPath: audioPath,
-		resampler: resampler,
-		speed:     1.0,
+		ctrl:     ctrl,
+		streamer: streamer,
+		format:   format,
+		codec:    codec,
+		source:   source,
+		closer:   streamer,
+		stretch:  stretch,
+		tap:      tap,
+		volume:   volume,
+		percent:  100,
+		output:   output,
+		speed:    1.0,
 	}, nil
 }
 
-func extractAudio(videoPath, audioPath string) error {
-	cmd := exec.Command("ffmpeg", "-i", videoPath, "-q:a", "0", "-map", "a", audioPath, "-y")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("ffmpeg command failed: %v\nOutput: %s", err, string(output))
-	}
-	return nil
-}
-
-func extractAudioFromYouTube(videoURL, audioPath string) error {
+// youTubeAudioURL asks yt-dlp for the URL of the best audio-only stream of a video
+func youTubeAudioURL(videoURL string) (string, error) {
 	executablePath, err := ytdlp.GetExecutablePath()
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer os.Remove(executablePath)
 
-	cmd := exec.Command(
-		executablePath,
-		"--no-playlist",
-		"--quiet",
-		"--progress",
-		"-x",
-		"--audio-format", "mp3",
-		"-o", audioPath,
-		videoURL,
-	)
-	output, err := cmd.CombinedOutput()
+	cmd := exec.Command(executablePath, "--no-playlist", "-f", "bestaudio/best", "-g", videoURL)
+	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("yt-dlp command failed: %v\nOutput: %s", err, string(output))
+		return "", fmt.Errorf("yt-dlp command failed: %v", err)
 	}
-	return nil
+	streamURL := strings.TrimSpace(strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0])
+	if streamURL == "" {
+		return "", fmt.Errorf("yt-dlp returned an empty stream URL")
+	}
+	return streamURL, nil
+}
+
+// Codec returns how the audio is being decoded
+func (ap *AudioPlayer) Codec() Codec {
+	return ap.codec
+}
+
+// SampleRate returns the sample rate of the audio
+func (ap *AudioPlayer) SampleRate() int {
+	return int(ap.format.SampleRate)
+}
+
+// Recent fills dst with the audio being heard right now, ending at the
+// current position. While paused it keeps returning the same samples.
+func (ap *AudioPlayer) Recent(dst [][2]float64) {
+	ap.tap.Recent(dst, ap.format.SampleRate.N(speakerBufferDuration))
+}
+
+// Duration returns the length of the audio. Streams decoded by ffmpeg have no
+// known length until they have been played to the end, so it is probed first.
+func (ap *AudioPlayer) Duration() (time.Duration, error) {
+	ap.output.Lock()
+	length := ap.streamer.Len()
+	ap.output.Unlock()
+	if length > 0 {
+		return ap.format.SampleRate.D(length), nil
+	}
+	if ap.source == "" {
+		return 0, fmt.Errorf("the length of the audio is unknown")
+	}
+	return ProbeDuration(ap.source)
 }
 
-// Play starts audio playback
-func (ap *AudioPlayer) Play() {
-	speaker.Init(ap.format.SampleRate, ap.format.SampleRate.N(time.Second/10))
-	speaker.Play(ap.ctrl)
+// Play starts audio playback. The output keeps being fed silence after the
+// stream ends, so seeking back into the stream resumes playback by itself.
+func (ap *AudioPlayer) Play() error {
+	return ap.output.Start(ap.format, ap.format.SampleRate.N(speakerBufferDuration), beep.StreamerFunc(ap.stream))
+}
+
+// stream reads from the controlled stream and pads the rest with silence
+func (ap *AudioPlayer) stream(samples [][2]float64) (int, bool) {
+	n, _ := ap.ctrl.Stream(samples)
+	for i := n; i < len(samples); i++ {
+		samples[i] = [2]float64{}
+	}
+	return len(samples), true
 }
 
-// SetSpeed adjusts the playback speed of the audio.
+// SetSpeed changes the playback speed of the audio while keeping its pitch.
 func (ap *AudioPlayer) SetSpeed(speed float64) {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
 	ap.speed = speed
-	if ap.resampler != nil {
-		speaker.Lock()
-		ap.resampler.SetRatio(ap.speed)
-		speaker.Unlock()
+	ap.output.Lock()
+	ap.stretch.SetSpeed(speed)
+	ap.output.Unlock()
+}
+
+// SetVolume sets the volume in percent, from 0 (silent) to MaxVolume. The
+// gain follows the percentage, so 50 halves the amplitude.
+func (ap *AudioPlayer) SetVolume(percent int) {
+	ap.output.Lock()
+	defer ap.output.Unlock()
+	ap.percent = max(0, min(percent, MaxVolume))
+	ap.applyVolume()
+}
+
+// SetMuted silences the audio without losing the volume. Muted audio keeps
+// playing, so it still drives the video clock.
+func (ap *AudioPlayer) SetMuted(muted bool) {
+	ap.output.Lock()
+	defer ap.output.Unlock()
+	ap.muted = muted
+	ap.applyVolume()
+}
+
+// applyVolume updates the volume stage. The caller must hold the output lock.
+func (ap *AudioPlayer) applyVolume() {
+	ap.volume.Silent = ap.muted || ap.percent == 0
+	if !ap.volume.Silent {
+		ap.volume.Volume = math.Log2(float64(ap.percent) / 100)
 	}
 }
 
 // Pause pauses audio playback
 func (ap *AudioPlayer) Pause() {
-	speaker.Lock()
+	ap.output.Lock()
 	ap.ctrl.Paused = true
-	speaker.Unlock()
+	ap.output.Unlock()
 }
 
 // Resume resumes audio playback
 func (ap *AudioPlayer) Resume() {
-	speaker.Lock()
+	ap.output.Lock()
 	ap.ctrl.Paused = false
-	speaker.Unlock()
+	ap.output.Unlock()
 }
 
 // Rewind rewinds the audio to the beginning
 func (ap *AudioPlayer) Rewind() error {
-	speaker.Lock()
-	defer speaker.Unlock()
+	ap.output.Lock()
+	defer ap.output.Unlock()
 
 	if err := ap.streamer.Seek(0); err != nil {
 		return err
 	}
 
-	// Re-create the resampler to clear its internal state.
-	ap.resampler = beep.Resample(4, ap.format.SampleRate, ap.format.SampleRate, ap.streamer)
-	ap.resampler.SetRatio(ap.speed)
-	ap.ctrl.Streamer = ap.resampler
+	// Drop audio the time stretcher buffered from the old position.
+	ap.stretch.Reset()
 	return nil
 }
 
 // Seek seeks the audio by the given duration.
 func (ap *AudioPlayer) Seek(duration time.Duration) error {
-	speaker.Lock()
-	defer speaker.Unlock()
+	ap.output.Lock()
+	currentPosition := ap.format.SampleRate.D(ap.streamer.Position())
+	ap.output.Unlock()
+
+	return ap.SeekTo(currentPosition + duration)
+}
 
-	currentPosition := ap.streamer.Position()
-	currentDuration := ap.format.SampleRate.D(currentPosition)
-	newDuration := currentDuration + duration
+// SeekTo seeks the audio to the given position from the beginning.
+func (ap *AudioPlayer) SeekTo(position time.Duration) error {
+	ap.output.Lock()
+	defer ap.output.Unlock()
 
-	if newDuration < 0 {
-		newDuration = 0
+	if position < 0 {
+		position = 0
 	}
 
-	newPosition := ap.format.SampleRate.N(newDuration)
+	newPosition := ap.format.SampleRate.N(position)
+	// The length is only known once the end has been decoded
 	if ap.streamer.Len() > 0 && newPosition >= ap.streamer.Len() {
 		newPosition = ap.streamer.Len() - 1
-	} else if ap.streamer.Len() <= 0 {
-		newPosition = 0
 	}
 
 	if err := ap.streamer.Seek(newPosition); err != nil {
 		return err
 	}
 
-	// Re-create the resampler to clear its internal state after a seek.
-	ap.resampler = beep.Resample(4, ap.format.SampleRate, ap.format.SampleRate, ap.streamer)
-	ap.resampler.SetRatio(ap.speed)
-	ap.ctrl.Streamer = ap.resampler
+	// Drop audio the time stretcher buffered from the old position.
+	ap.stretch.Reset()
 
 	return nil
 }
 
-// Close closes the audio player and cleans up resources
-func (ap *AudioPlayer) Close() {
+// Position returns the position of the audio currently being heard. Audio
+// still waiting in the speaker buffer is not counted, so the value can be
+// used as the master clock for video playback.
+func (ap *AudioPlayer) Position() time.Duration {
+	ap.output.Lock()
+	position := ap.format.SampleRate.D(ap.streamer.Position() - ap.stretch.Buffered())
+	ap.output.Unlock()
+
+	ap.mutex.Lock()
+	speed := ap.speed
+	ap.mutex.Unlock()
+
+	// The speaker buffer holds output samples, which cover speed times as much source audio
+	position -= time.Duration(float64(speakerBufferDuration) * speed)
+	if position < 0 {
+		position = 0
+	}
+	return position
+}
+
+// Finished reports whether the whole audio stream has been played.
+func (ap *AudioPlayer) Finished() bool {
+	ap.output.Lock()
+	defer ap.output.Unlock()
+	return ap.streamer.Len() > 0 && ap.streamer.Position() >= ap.streamer.Len()
+}
+
+// Close closes the audio player and cleans up resources. It returns the
+// error of finishing a WAV output file, if any.
+func (ap *AudioPlayer) Close() error {
 	if ap.closer != nil {
+		ap.output.Lock()
 		ap.closer.Close()
+		ap.output.Unlock()
 	}
-	speaker.Close()
-	// The audio file is now managed by the cache, so we don't remove it here.
+	return ap.output.Close()
 }
@@ -0,0 +1,277 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// decodeSampleRate is the rate ffmpeg resamples every source to
+const decodeSampleRate beep.SampleRate = 44100
+
+// decodeBufferSize is the size of the pipe buffer; about 0.4 seconds of audio
+const decodeBufferSize = 64 * 1024
+
+// bytesPerSample is the size of one stereo signed 16-bit sample
+const bytesPerSample = 4
+
+var (
+	// ErrFFmpegNotFound is returned when there is no ffmpeg to decode the audio with
+	ErrFFmpegNotFound = errors.New("ffmpeg was not found in PATH; install it to play audio")
+	// ErrNoAudioTrack is returned when the source has no audio to play
+	ErrNoAudioTrack = errors.New("the source has no audio track")
+)
+
+// CheckAvailable reports whether the ffmpeg command needed to decode the
+// audio of videos and stream URLs is installed
+func CheckAvailable() error {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return ErrFFmpegNotFound
+	}
+	return nil
+}
+
+// Decoder streams the audio track of a file or URL as PCM from an ffmpeg
+// process, without writing anything to disk. Seeking restarts ffmpeg at the
+// new position. It implements beep.StreamSeeker.
+//
+// gocv only demuxes and decodes video, so containers and codecs that are not
+// decoded in Go (see Open) still need the ffmpeg command.
+//
+// The length of the stream is unknown until the end has been decoded once;
+// until then Len returns 0.
+type Decoder struct {
+	source string
+
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	reader *bufio.Reader
+	stderr bytes.Buffer
+	buffer []byte
+
+	// startedAt is the sample the running process started decoding from
+	startedAt int
+	position  int
+	length    int
+	// done is set once the running process reached the end of the stream
+	done bool
+	err  error
+}
+
+// NewDecoder starts decoding the audio of source, which can be a path or a
+// stream URL. It fails up front when ffmpeg is missing or the source has no
+// audio track.
+func NewDecoder(source string) (*Decoder, error) {
+	if err := CheckAvailable(); err != nil {
+		return nil, err
+	}
+
+	d := &Decoder{source: source}
+	if err := d.start(0); err != nil {
+		return nil, err
+	}
+	// Wait for the first samples so a source without audio fails here
+	if _, err := d.reader.Peek(bytesPerSample); err != nil {
+		d.stop()
+		message := strings.TrimSpace(d.stderr.String())
+		if strings.Contains(message, "matches no streams") || message == "" {
+			return nil, ErrNoAudioTrack
+		}
+		return nil, fmt.Errorf("ffmpeg failed to decode audio: %s", lastLine(message))
+	}
+	return d, nil
+}
+
+// Format returns the format of the decoded samples
+func (d *Decoder) Format() beep.Format {
+	return pcmFormat
+}
+
+// start launches ffmpeg decoding from the given sample
+func (d *Decoder) start(position int) error {
+	seconds := decodeSampleRate.D(position).Seconds()
+	cmd := exec.Command("ffmpeg",
+		"-nostdin",
+		"-loglevel", "error",
+		"-ss", strconv.FormatFloat(seconds, 'f', 6, 64),
+		"-i", d.source,
+		"-map", "0:a:0",
+		"-vn", "-sn", "-dn",
+		"-f", "s16le",
+		"-acodec", "pcm_s16le",
+		"-ac", "2",
+		"-ar", strconv.Itoa(int(decodeSampleRate)),
+		"pipe:1",
+	)
+	d.stderr.Reset()
+	cmd.Stderr = &d.stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create ffmpeg pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ffmpeg: %v", err)
+	}
+
+	d.cmd = cmd
+	d.stdout = stdout
+	d.reader = bufio.NewReaderSize(stdout, decodeBufferSize)
+	d.startedAt = position
+	d.position = position
+	d.done = false
+	return nil
+}
+
+// stop ends the running ffmpeg process
+func (d *Decoder) stop() {
+	if d.cmd == nil {
+		return
+	}
+	d.stdout.Close()
+	d.cmd.Process.Kill()
+	d.cmd.Wait()
+	d.cmd = nil
+}
+
+// Stream implements beep.Streamer
+func (d *Decoder) Stream(samples [][2]float64) (n int, ok bool) {
+	if d.cmd == nil || d.done {
+		return 0, false
+	}
+
+	size := len(samples) * bytesPerSample
+	if cap(d.buffer) < size {
+		d.buffer = make([]byte, size)
+	}
+	buffer := d.buffer[:size]
+
+	read, err := io.ReadFull(d.reader, buffer)
+	n = decodeS16LE(buffer[:read], samples)
+	d.position += n
+
+	if err != nil {
+		// The end of the pipe is the end of the stream, unless ffmpeg reported a failure
+		d.done = true
+		d.stdout.Close()
+		if waitErr := d.cmd.Wait(); waitErr != nil {
+			d.err = fmt.Errorf("ffmpeg failed to decode audio: %s", lastLine(d.stderr.String()))
+		} else if d.position > d.startedAt || d.startedAt == 0 {
+			// A process started past the end decodes nothing and tells nothing about the length
+			d.length = d.position
+		}
+		d.cmd = nil
+	}
+	return n, n > 0
+}
+
+// Err implements beep.Streamer
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Len returns the number of samples in the stream, or 0 while it is not known yet
+func (d *Decoder) Len() int {
+	return d.length
+}
+
+// Position returns the index of the next sample to be streamed
+func (d *Decoder) Position() int {
+	return d.position
+}
+
+// Seek restarts decoding at sample p
+func (d *Decoder) Seek(p int) error {
+	if p < 0 {
+		p = 0
+	}
+	if d.length > 0 && p > d.length {
+		p = d.length
+	}
+	if d.cmd != nil && p == d.position {
+		return nil
+	}
+	d.stop()
+	d.err = nil
+	return d.start(p)
+}
+
+// Close stops decoding
+func (d *Decoder) Close() error {
+	d.stop()
+	d.done = true
+	return nil
+}
+
+var (
+	// durationPattern finds the length ffmpeg prints when it opens a file
+	durationPattern = regexp.MustCompile(`Duration: (\d+):(\d+):(\d+(?:\.\d+)?)`)
+	// videoStreamPattern finds the video streams ffmpeg lists when it opens a file
+	videoStreamPattern = regexp.MustCompile(`(?m)^\s*Stream #\d+:\d+.*: Video: .*$`)
+)
+
+// probe returns what ffmpeg prints about source when it opens it
+func probe(source string) ([]byte, error) {
+	if err := CheckAvailable(); err != nil {
+		return nil, err
+	}
+	// Without an output ffmpeg only prints what it found and exits with an error
+	output, _ := exec.Command("ffmpeg", "-hide_banner", "-nostdin", "-i", source).CombinedOutput()
+	return output, nil
+}
+
+// ProbeDuration asks ffmpeg for the length of source
+func ProbeDuration(source string) (time.Duration, error) {
+	output, err := probe(source)
+	if err != nil {
+		return 0, err
+	}
+	match := durationPattern.FindSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("ffmpeg could not tell the length of %s", source)
+	}
+	hours, _ := strconv.Atoi(string(match[1]))
+	minutes, _ := strconv.Atoi(string(match[2]))
+	seconds, _ := strconv.ParseFloat(string(match[3]), 64)
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second)), nil
+}
+
+// ProbeVideoStream asks ffmpeg whether source has a video stream. Cover art
+// attached to audio files is not counted as one.
+func ProbeVideoStream(source string) (bool, error) {
+	output, err := probe(source)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Contains(output, []byte("Input #")) {
+		return false, fmt.Errorf("ffmpeg could not open %s: %s", source, lastLine(string(output)))
+	}
+	return hasVideoStream(output), nil
+}
+
+// hasVideoStream reports whether the streams ffmpeg listed include a video
+// stream that is not an attached picture
+func hasVideoStream(output []byte) bool {
+	for _, stream := range videoStreamPattern.FindAll(output, -1) {
+		if !bytes.Contains(stream, []byte("(attached pic)")) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastLine returns the last non-empty line of ffmpeg's error output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
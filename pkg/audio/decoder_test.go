@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestHasVideoStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   bool
+	}{
+		{
+			name: "video and audio",
+			output: `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'test.mp4':
+  Duration: 00:00:10.00, start: 0.000000, bitrate: 1205 kb/s
+  Stream #0:0[0x1](und): Video: h264 (High) (avc1 / 0x31637661), yuv420p, 1280x720, 1070 kb/s, 30 fps (default)
+  Stream #0:1[0x2](und): Audio: aac (LC) (mp4a / 0x6134706D), 44100 Hz, stereo, fltp, 128 kb/s (default)`,
+			want: true,
+		},
+		{
+			name: "audio only",
+			output: `Input #0, matroska,webm, from 'song.mka':
+  Duration: 00:03:00.00, start: 0.000000, bitrate: 160 kb/s
+  Stream #0:0: Audio: opus, 48000 Hz, stereo, fltp (default)`,
+			want: false,
+		},
+		{
+			name: "cover art",
+			output: `Input #0, mp3, from 'song.mp3':
+  Duration: 00:03:00.00, start: 0.025057, bitrate: 320 kb/s
+  Stream #0:0: Audio: mp3, 44100 Hz, stereo, fltp, 320 kb/s
+  Stream #0:1: Video: mjpeg (Baseline), yuvj420p(pc, bt470bg/unknown/unknown), 500x500 [SAR 1:1 DAR 1:1], 90k tbr, 90k tbn (attached pic)`,
+			want: false,
+		},
+		{
+			name:   "metadata mentioning video",
+			output: "Input #0, ogg, from 'talk.ogg':\n    title           : Video: the talk\n  Stream #0:0: Audio: vorbis, 44100 Hz, stereo, fltp",
+			want:   false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hasVideoStream([]byte(test.output)); got != test.want {
+				t.Errorf("hasVideoStream() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+// fakeFFmpeg puts an ffmpeg script first in PATH that decodes half a second
+// of silence, less whatever -ss skips
+func fakeFFmpeg(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ffmpeg is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+ss=0
+while [ $# -gt 0 ]; do
+	[ "$1" = "-ss" ] && ss=$2
+	shift
+done
+head -c $(awk -v ss="$ss" 'BEGIN { n = int((0.5 - ss) * 44100); if (n < 0) n = 0; print n * 4 }') /dev/zero
+`
+	if err := os.WriteFile(filepath.Join(dir, "ffmpeg"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// drainDecoder streams d until it ends
+func drainDecoder(d *Decoder) {
+	samples := make([][2]float64, 1024)
+	for {
+		if _, ok := d.Stream(samples); !ok {
+			return
+		}
+	}
+}
+
+func TestDecoderLength(t *testing.T) {
+	fakeFFmpeg(t)
+	const length = 22050
+	d, err := NewDecoder("test.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	// Nothing is decoded past the end, which must not be taken as the length
+	if err := d.Seek(2 * length); err != nil {
+		t.Fatal(err)
+	}
+	drainDecoder(d)
+	if got := d.Len(); got != 0 {
+		t.Errorf("Len() = %d after seeking past the end, want 0 while unknown", got)
+	}
+
+	if err := d.Seek(length / 2); err != nil {
+		t.Fatal(err)
+	}
+	drainDecoder(d)
+	if got := d.Len(); got != length {
+		t.Errorf("Len() = %d after decoding to the end, want %d", got, length)
+	}
+
+	// Once known, seeks past the end stop at it
+	if err := d.Seek(2 * length); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Position(); got != length {
+		t.Errorf("Position() = %d after seeking past the end, want %d", got, length)
+	}
+	drainDecoder(d)
+	if got := d.Len(); got != length {
+		t.Errorf("Len() = %d after seeking past the end again, want %d", got, length)
+	}
+}
@@ -0,0 +1,223 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+)
+
+// Output names accepted by NewOutput besides a .wav path
+const (
+	OutputSpeaker = "speaker"
+	OutputNull    = "null"
+)
+
+// Output is where the mixed audio goes. The stream passed to Start is pulled
+// in real time; Lock and Unlock guard changes to it while it is playing.
+type Output interface {
+	Start(format beep.Format, bufferSize int, streamer beep.Streamer) error
+	Lock()
+	Unlock()
+	Close() error
+}
+
+// NewOutput creates the output named by spec: "speaker" (or empty) for the
+// sound card, "null" to discard the audio, or a path ending in .wav to write
+// it to a file. Null and WAV outputs need no audio device, so playback also
+// works on headless machines.
+func NewOutput(spec string) (Output, error) {
+	switch {
+	case spec == "" || spec == OutputSpeaker:
+		return &speakerOutput{}, nil
+	case spec == OutputNull:
+		return &pacedOutput{}, nil
+	case strings.EqualFold(filepath.Ext(spec), ".wav"):
+		return &pacedOutput{path: spec}, nil
+	default:
+		return nil, fmt.Errorf("invalid audio output %q (expected %s, %s or a .wav file)", spec, OutputSpeaker, OutputNull)
+	}
+}
+
+// speakerOutput plays through the default sound card
+type speakerOutput struct{}
+
+func (speakerOutput) Start(format beep.Format, bufferSize int, streamer beep.Streamer) error {
+	if err := speaker.Init(format.SampleRate, bufferSize); err != nil {
+		return fmt.Errorf("failed to open audio device: %v", err)
+	}
+	speaker.Play(streamer)
+	return nil
+}
+
+func (speakerOutput) Lock()   { speaker.Lock() }
+func (speakerOutput) Unlock() { speaker.Unlock() }
+
+func (speakerOutput) Close() error {
+	speaker.Close()
+	return nil
+}
+
+// pacedOutput pulls the stream at the speed a sound card would, discarding it
+// or writing it to a WAV file
+type pacedOutput struct {
+	mutex sync.Mutex
+	path  string
+	wav   *wavWriter
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (o *pacedOutput) Start(format beep.Format, bufferSize int, streamer beep.Streamer) error {
+	if o.path != "" {
+		wav, err := createWAV(o.path, format.SampleRate)
+		if err != nil {
+			return err
+		}
+		o.wav = wav
+	}
+	o.stop = make(chan struct{})
+	o.done = make(chan struct{})
+	go o.run(format.SampleRate, bufferSize, streamer)
+	return nil
+}
+
+// run streams as many samples as have played since the start, one buffer at a time
+func (o *pacedOutput) run(sampleRate beep.SampleRate, bufferSize int, streamer beep.Streamer) {
+	defer close(o.done)
+
+	samples := make([][2]float64, bufferSize)
+	ticker := time.NewTicker(sampleRate.D(bufferSize))
+	defer ticker.Stop()
+
+	start := time.Now()
+	played := 0
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		}
+		for played < sampleRate.N(time.Since(start)) {
+			chunk := samples[:min(bufferSize, sampleRate.N(time.Since(start))-played)]
+			o.mutex.Lock()
+			n, _ := streamer.Stream(chunk)
+			o.mutex.Unlock()
+			// Like a sound card, a stream that runs dry is heard as silence
+			for i := n; i < len(chunk); i++ {
+				chunk[i] = [2]float64{}
+			}
+			if o.wav != nil {
+				o.wav.write(chunk)
+			}
+			played += len(chunk)
+		}
+	}
+}
+
+func (o *pacedOutput) Lock()   { o.mutex.Lock() }
+func (o *pacedOutput) Unlock() { o.mutex.Unlock() }
+
+// Close stops pulling the stream and finishes the WAV file
+func (o *pacedOutput) Close() error {
+	var err error
+	o.stopOnce.Do(func() {
+		if o.stop != nil {
+			close(o.stop)
+			<-o.done
+		}
+		if o.wav != nil {
+			err = o.wav.close()
+		}
+	})
+	return err
+}
+
+// wavHeaderSize is the size of the canonical 44 byte PCM WAV header
+const wavHeaderSize = 44
+
+// wavWriter writes 16-bit stereo PCM to a WAV file as it is played. The sizes
+// in the header are filled in when it is closed.
+type wavWriter struct {
+	file       *os.File
+	sampleRate beep.SampleRate
+	buffer     []byte
+	size       int
+	err        error
+}
+
+func createWAV(path string, sampleRate beep.SampleRate) (*wavWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audio output file: %v", err)
+	}
+	w := &wavWriter{file: file, sampleRate: sampleRate}
+	if err := w.writeHeader(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write audio output file: %v", err)
+	}
+	return w, nil
+}
+
+func (w *wavWriter) writeHeader() error {
+	header := make([]byte, wavHeaderSize)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(wavHeaderSize-8+w.size))
+	copy(header[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:], 2)
+	binary.LittleEndian.PutUint32(header[24:], uint32(w.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(int(w.sampleRate)*bytesPerSample))
+	binary.LittleEndian.PutUint16(header[32:], bytesPerSample)
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], uint32(w.size))
+	_, err := w.file.WriteAt(header, 0)
+	return err
+}
+
+// write appends samples, clipped to 16 bits. The first error is kept for close.
+func (w *wavWriter) write(samples [][2]float64) {
+	if w.err != nil {
+		return
+	}
+	size := len(samples) * bytesPerSample
+	if cap(w.buffer) < size {
+		w.buffer = make([]byte, size)
+	}
+	buffer := w.buffer[:size]
+	for i, sample := range samples {
+		for c := 0; c < 2; c++ {
+			value := int16(max(-1, min(sample[c], 1)) * (1<<15 - 1))
+			binary.LittleEndian.PutUint16(buffer[i*bytesPerSample+c*2:], uint16(value))
+		}
+	}
+	if _, err := w.file.WriteAt(buffer, int64(wavHeaderSize+w.size)); err != nil {
+		w.err = err
+		return
+	}
+	w.size += size
+}
+
+func (w *wavWriter) close() error {
+	err := w.err
+	if headerErr := w.writeHeader(); err == nil {
+		err = headerErr
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write audio output file: %v", err)
+	}
+	return nil
+}
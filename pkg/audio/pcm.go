@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/faiface/beep"
+)
+
+// pcmFormat is the format of raw PCM: signed 16-bit little-endian stereo at 44.1 kHz
+var pcmFormat = beep.Format{SampleRate: decodeSampleRate, NumChannels: 2, Precision: 2}
+
+// PCMStreamer plays raw signed 16-bit little-endian stereo samples from a
+// seekable reader, such as a file written by ffmpeg with -f s16le.
+type PCMStreamer struct {
+	r        io.ReadSeekCloser
+	buffer   []byte
+	position int
+	length   int
+	err      error
+}
+
+// NewPCMStreamer streams the raw PCM in r; its length is taken from the size of r
+func NewPCMStreamer(r io.ReadSeekCloser) (*PCMStreamer, beep.Format, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, beep.Format{}, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, beep.Format{}, err
+	}
+	return &PCMStreamer{r: r, length: int(size / bytesPerSample)}, pcmFormat, nil
+}
+
+// Stream implements beep.Streamer
+func (s *PCMStreamer) Stream(samples [][2]float64) (n int, ok bool) {
+	size := len(samples) * bytesPerSample
+	if cap(s.buffer) < size {
+		s.buffer = make([]byte, size)
+	}
+	buffer := s.buffer[:size]
+
+	read, err := io.ReadFull(s.r, buffer)
+	n = decodeS16LE(buffer[:read], samples)
+	s.position += n
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		s.err = err
+	}
+	return n, n > 0
+}
+
+// Err implements beep.Streamer
+func (s *PCMStreamer) Err() error {
+	return s.err
+}
+
+// Len returns the number of samples in the stream
+func (s *PCMStreamer) Len() int {
+	return s.length
+}
+
+// Position returns the index of the next sample to be streamed
+func (s *PCMStreamer) Position() int {
+	return s.position
+}
+
+// Seek moves to sample p
+func (s *PCMStreamer) Seek(p int) error {
+	if p < 0 || p > s.length {
+		return fmt.Errorf("pcm: seek position %d out of range [0, %d]", p, s.length)
+	}
+	if _, err := s.r.Seek(int64(p)*bytesPerSample, io.SeekStart); err != nil {
+		return err
+	}
+	s.position = p
+	return nil
+}
+
+// Close closes the underlying reader
+func (s *PCMStreamer) Close() error {
+	return s.r.Close()
+}
+
+// decodeS16LE converts whole stereo s16le samples from data into samples and returns how many it converted
+func decodeS16LE(data []byte, samples [][2]float64) int {
+	n := min(len(data)/bytesPerSample, len(samples))
+	for i := 0; i < n; i++ {
+		b := data[i*bytesPerSample:]
+		samples[i][0] = float64(int16(uint16(b[0])|uint16(b[1])<<8)) / (1 << 15)
+		samples[i][1] = float64(int16(uint16(b[2])|uint16(b[3])<<8)) / (1 << 15)
+	}
+	return n
+}
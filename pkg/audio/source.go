@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/flac"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/vorbis"
+	"github.com/faiface/beep/wav"
+)
+
+// Codec is how an audio source is decoded
+type Codec string
+
+const (
+	CodecWAV    Codec = "wav"
+	CodecFLAC   Codec = "flac"
+	CodecVorbis Codec = "vorbis"
+	CodecMP3    Codec = "mp3"
+	// CodecPCM is raw signed 16-bit little-endian stereo at 44.1 kHz, as ffmpeg's s16le output
+	CodecPCM Codec = "pcm"
+	// CodecFFmpeg covers everything else, such as the audio track of a video, decoded by ffmpeg
+	CodecFFmpeg Codec = "ffmpeg"
+)
+
+// sniffSize is how many bytes DetectCodec looks at
+const sniffSize = 64
+
+// rawPCMExtensions are the file extensions treated as raw s16le PCM, which has no header to detect
+var rawPCMExtensions = []string{".pcm", ".raw", ".s16le"}
+
+// DetectCodec picks the decoder for a file from its first bytes, or from its
+// extension for headerless raw PCM. Anything unknown is left to ffmpeg.
+func DetectCodec(header []byte, name string) Codec {
+	switch {
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return CodecWAV
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return CodecFLAC
+	case bytes.HasPrefix(header, []byte("OggS")) && bytes.Contains(header, []byte("\x01vorbis")):
+		// Ogg can also carry Opus or FLAC, which are left to ffmpeg
+		return CodecVorbis
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return CodecMP3
+	case len(header) >= 2 && header[0] == 0xff && header[1]&0xe0 == 0xe0 && header[1]&0x06 != 0:
+		// An MPEG audio frame sync; layer bits of zero would be AAC in ADTS
+		return CodecMP3
+	}
+
+	extension := strings.ToLower(filepath.Ext(name))
+	for _, raw := range rawPCMExtensions {
+		if extension == raw {
+			return CodecPCM
+		}
+	}
+	return CodecFFmpeg
+}
+
+// DetectFileCodec picks the decoder for a local file from its first bytes.
+// URLs and files that cannot be read are left to ffmpeg.
+func DetectFileCodec(source string) Codec {
+	file, err := os.Open(source)
+	if err != nil {
+		return CodecFFmpeg
+	}
+	defer file.Close()
+
+	header := make([]byte, sniffSize)
+	n, _ := io.ReadFull(file, header)
+	return DetectCodec(header[:n], source)
+}
+
+// CheckSource reports why the audio of source cannot be played before it is
+// opened. Sources decoded in Go always can; anything else needs ffmpeg.
+func CheckSource(source string) error {
+	if DetectFileCodec(source) != CodecFFmpeg {
+		return nil
+	}
+	return CheckAvailable()
+}
+
+// Open opens an audio source for playback. Local WAV, FLAC, Ogg Vorbis, MP3
+// and raw PCM files are decoded in Go; other files and URLs, including the
+// audio track of a video, are decoded by ffmpeg.
+func Open(source string) (beep.StreamSeekCloser, beep.Format, Codec, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		if os.IsNotExist(err) && strings.Contains(source, "://") {
+			// A stream URL
+			return openFFmpeg(source)
+		}
+		return nil, beep.Format{}, "", fmt.Errorf("failed to open audio source: %v", err)
+	}
+
+	header := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		file.Close()
+		return nil, beep.Format{}, "", fmt.Errorf("failed to read audio source: %v", err)
+	}
+	codec := DetectCodec(header[:n], source)
+	if codec == CodecFFmpeg {
+		file.Close()
+		return openFFmpeg(source)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, beep.Format{}, "", fmt.Errorf("failed to read audio source: %v", err)
+	}
+
+	var streamer beep.StreamSeekCloser
+	var format beep.Format
+	switch codec {
+	case CodecWAV:
+		streamer, format, err = wav.Decode(file)
+	case CodecFLAC:
+		streamer, format, err = flac.Decode(file)
+	case CodecVorbis:
+		streamer, format, err = vorbis.Decode(file)
+	case CodecMP3:
+		streamer, format, err = mp3.Decode(file)
+	case CodecPCM:
+		streamer, format, err = NewPCMStreamer(file)
+	}
+	if err != nil {
+		file.Close()
+		return nil, beep.Format{}, "", fmt.Errorf("failed to decode %s audio: %v", codec, err)
+	}
+	return streamer, format, codec, nil
+}
+
+// openFFmpeg decodes source through an ffmpeg PCM pipe
+func openFFmpeg(source string) (beep.StreamSeekCloser, beep.Format, Codec, error) {
+	decoder, err := NewDecoder(source)
+	if err != nil {
+		return nil, beep.Format{}, "", err
+	}
+	return decoder, decoder.Format(), CodecFFmpeg, nil
+}
@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectCodec(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		file   string
+		want   Codec
+	}{
+		{"WAV", "RIFF\x24\x08\x00\x00WAVEfmt ", "a.bin", CodecWAV},
+		{"RIFF that is not WAV", "RIFF\x24\x08\x00\x00AVI LIST", "a.avi", CodecFFmpeg},
+		{"short RIFF", "RIFF\x24\x08", "a.wav", CodecFFmpeg},
+		{"FLAC", "fLaC\x00\x00\x00\x22", "a", CodecFLAC},
+		{"Ogg Vorbis", "OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00\x01vorbis", "a.ogg", CodecVorbis},
+		{"Ogg Opus", "OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00OpusHead", "a.opus", CodecFFmpeg},
+		{"MP3 with ID3", "ID3\x04\x00\x00", "a", CodecMP3},
+		{"MPEG 1 layer 3 sync", "\xff\xfb\x90\x00", "a", CodecMP3},
+		{"MPEG 2 layer 3 sync", "\xff\xf3\x48\x00", "a", CodecMP3},
+		{"AAC in ADTS", "\xff\xf1\x50\x80", "a.aac", CodecFFmpeg},
+		{"not a sync", "\xff\x1b\x90\x00", "a", CodecFFmpeg},
+		{"MP4", "\x00\x00\x00\x18ftypmp42", "a.mp4", CodecFFmpeg},
+		{"raw PCM", "\x01\x02\x03\x04", "a.pcm", CodecPCM},
+		{"raw PCM extension in upper case", "\x01\x02\x03\x04", "a.S16LE", CodecPCM},
+		{"raw extension", "", "dir/a.raw", CodecPCM},
+		{"header wins over the extension", "fLaC", "a.pcm", CodecFLAC},
+		{"empty", "", "a", CodecFFmpeg},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DetectCodec([]byte(test.header), test.file); got != test.want {
+				t.Errorf("DetectCodec(%q, %q) = %s, want %s", test.header, test.file, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDetectFileCodec(t *testing.T) {
+	dir := t.TempDir()
+	flac := filepath.Join(dir, "song")
+	if err := os.WriteFile(flac, []byte("fLaC\x00\x00\x00\x22"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source string
+		want   Codec
+	}{
+		{flac, CodecFLAC},
+		{filepath.Join(dir, "missing.wav"), CodecFFmpeg},
+		{"https://example.com/song.flac", CodecFFmpeg},
+	}
+	for _, test := range tests {
+		if got := DetectFileCodec(test.source); got != test.want {
+			t.Errorf("DetectFileCodec(%q) = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"sync"
+
+	"github.com/faiface/beep"
+)
+
+// tapHistory is how many of the latest samples a Tap keeps
+const tapHistory = 1 << 15
+
+// Tap passes a stream through unchanged and keeps its latest samples, so what
+// is being played can be shown while it plays, for example by a visualizer
+type Tap struct {
+	streamer beep.Streamer
+
+	mutex   sync.Mutex
+	history [][2]float64
+	// next is where the next sample goes in the circular history
+	next int
+}
+
+// NewTap wraps streamer in a Tap
+func NewTap(streamer beep.Streamer) *Tap {
+	return &Tap{streamer: streamer, history: make([][2]float64, tapHistory)}
+}
+
+// Stream implements beep.Streamer
+func (t *Tap) Stream(samples [][2]float64) (n int, ok bool) {
+	n, ok = t.streamer.Stream(samples)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, sample := range samples[:n] {
+		t.history[t.next] = sample
+		t.next = (t.next + 1) % len(t.history)
+	}
+	return n, ok
+}
+
+// Err implements beep.Streamer
+func (t *Tap) Err() error {
+	return t.streamer.Err()
+}
+
+// Recent fills dst with the samples that ended delay samples before the
+// latest one. The delay skips audio that is still waiting in the output buffer.
+func (t *Tap) Recent(dst [][2]float64, delay int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	size := len(t.history)
+	delay = max(0, min(delay, size-len(dst)))
+	start := t.next - delay - len(dst)
+	for i := range dst {
+		dst[i] = t.history[((start+i)%size+size)%size]
+	}
+}
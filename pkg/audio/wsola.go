@@ -0,0 +1,305 @@
+package audio
+
+import (
+	"math"
+	"sync"
+
+	"github.com/faiface/beep"
+)
+
+const (
+	// wsolaFrameDuration is the length of each overlap-added frame. Around 40 ms
+	// keeps speech and music intelligible without audible echo.
+	wsolaFrameDuration = 0.04
+	// wsolaToleranceRatio is the search range around the nominal analysis
+	// position, relative to the frame length
+	wsolaToleranceRatio = 0.25
+)
+
+// TimeStretch changes the tempo of a stream without changing its pitch using
+// WSOLA (waveform similarity overlap-add). Each output frame is taken from
+// near the position the speed calls for, shifted so its waveform lines up with
+// the end of the previous frame, and cross-faded with a Hann window.
+//
+// At speed 1 the source is passed through untouched.
+type TimeStretch struct {
+	mutex  sync.Mutex
+	source beep.Streamer
+	speed  float64
+
+	frameLen  int // N, samples per frame
+	hop       int // synthesis hop, N/2
+	tolerance int // maximum shift searched on either side of the nominal position
+	window    []float64
+
+	// input holds source samples starting at the absolute sample index inputStart
+	input      [][2]float64
+	inputStart int
+	sourceDone bool
+
+	// analysis is the nominal absolute position of the next frame
+	analysis float64
+	// prevStart is the absolute start of the previous frame, or -1 before the first one
+	prevStart int
+	// tail is the windowed second half of the previous frame, waiting to be overlapped
+	tail [][2]float64
+	// output holds finished samples not yet returned by Stream
+	output [][2]float64
+
+	// pending holds samples read while stretching that are played before the
+	// source at speed 1, so changing the speed never skips audio
+	pending [][2]float64
+}
+
+// NewTimeStretch wraps source so it plays at the given speed with its pitch preserved
+func NewTimeStretch(source beep.Streamer, sampleRate beep.SampleRate, speed float64) *TimeStretch {
+	frameLen := int(float64(sampleRate) * wsolaFrameDuration)
+	frameLen -= frameLen % 2
+	if frameLen < 64 {
+		frameLen = 64
+	}
+
+	window := make([]float64, frameLen)
+	for i := range window {
+		// A periodic Hann window sums to one when overlapped by half
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(frameLen))
+	}
+
+	ts := &TimeStretch{
+		source:    source,
+		frameLen:  frameLen,
+		hop:       frameLen / 2,
+		tolerance: int(float64(frameLen) * wsolaToleranceRatio),
+		window:    window,
+		speed:     1,
+	}
+	ts.reset()
+	ts.SetSpeed(speed)
+	return ts
+}
+
+// Speed returns the current playback speed
+func (ts *TimeStretch) Speed() float64 {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	return ts.speed
+}
+
+// SetSpeed changes the playback speed; 2 plays twice as fast at the same pitch
+func (ts *TimeStretch) SetSpeed(speed float64) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if speed <= 0 {
+		speed = 1
+	}
+	switch {
+	case speed == 1 && ts.speed != 1:
+		ts.stopStretching()
+	case speed != 1 && ts.speed == 1:
+		ts.startStretching()
+	}
+	ts.speed = speed
+}
+
+// stopStretching switches to pass-through. The finished output is played
+// first, then the input from where the next frame would naturally continue the
+// last one: the fading tail and the rising start of that frame add up to the
+// input itself, so the tail is dropped without a seam.
+func (ts *TimeStretch) stopStretching() {
+	resume := int(ts.analysis)
+	if ts.prevStart >= 0 {
+		resume = ts.prevStart + ts.hop
+	}
+	pending := append(ts.pending[:0], ts.output...)
+	if offset := resume - ts.inputStart; offset >= 0 && offset < len(ts.input) {
+		pending = append(pending, ts.input[offset:]...)
+	}
+	ts.pending = pending
+	ts.input = ts.input[:0]
+	ts.clearFrames()
+}
+
+// startStretching switches from pass-through to stretching, starting with the
+// samples still pending from before
+func (ts *TimeStretch) startStretching() {
+	ts.input = append(ts.input[:0], ts.pending...)
+	ts.pending = ts.pending[:0]
+	ts.clearFrames()
+}
+
+// clearFrames forgets the overlap and analysis state so the next frame starts
+// at the beginning of the input
+func (ts *TimeStretch) clearFrames() {
+	ts.inputStart = 0
+	ts.analysis = 0
+	ts.prevStart = -1
+	ts.tail = nil
+	ts.output = ts.output[:0]
+}
+
+// Reset drops buffered samples. It must be called after seeking the source.
+func (ts *TimeStretch) Reset() {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	ts.reset()
+}
+
+func (ts *TimeStretch) reset() {
+	ts.input = ts.input[:0]
+	ts.pending = ts.pending[:0]
+	ts.sourceDone = false
+	ts.clearFrames()
+}
+
+// Buffered returns how many source samples have been read ahead of what has been returned by Stream
+func (ts *TimeStretch) Buffered() int {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if ts.speed == 1 {
+		return len(ts.pending)
+	}
+	buffered := ts.inputStart + len(ts.input) - int(ts.analysis)
+	buffered += int(float64(len(ts.output)+len(ts.tail)) * ts.speed)
+	if buffered < 0 {
+		return 0
+	}
+	return buffered
+}
+
+// Stream implements beep.Streamer
+func (ts *TimeStretch) Stream(samples [][2]float64) (n int, ok bool) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if ts.speed == 1 {
+		n = copy(samples, ts.pending)
+		ts.pending = ts.pending[n:]
+		if n == len(samples) || ts.sourceDone {
+			return n, n > 0
+		}
+		m, ok := ts.source.Stream(samples[n:])
+		return n + m, ok || n > 0
+	}
+
+	for n < len(samples) {
+		if len(ts.output) == 0 && !ts.nextFrame() {
+			break
+		}
+		copied := copy(samples[n:], ts.output)
+		ts.output = ts.output[copied:]
+		n += copied
+	}
+	return n, n > 0
+}
+
+// Err implements beep.Streamer
+func (ts *TimeStretch) Err() error {
+	return ts.source.Err()
+}
+
+// nextFrame overlap-adds one more frame into the output. It returns false once the source is exhausted.
+func (ts *TimeStretch) nextFrame() bool {
+	nominal := int(ts.analysis)
+	if !ts.fill(nominal + ts.tolerance + ts.frameLen) {
+		// Flush the last half frame so the stream ends without cutting off
+		if len(ts.tail) > 0 {
+			ts.output = append(ts.output[:0], ts.tail...)
+			ts.tail = nil
+			return true
+		}
+		return false
+	}
+
+	start := nominal
+	if ts.prevStart >= 0 {
+		start = ts.bestStart(nominal)
+	}
+
+	// Window the chosen frame; its first half completes the previous frame's tail
+	frame := ts.input[start-ts.inputStart : start-ts.inputStart+ts.frameLen]
+	out := ts.output[:0]
+	for i := 0; i < ts.hop; i++ {
+		var sample [2]float64
+		for c := 0; c < 2; c++ {
+			sample[c] = frame[i][c] * ts.window[i]
+			if ts.tail != nil {
+				sample[c] += ts.tail[i][c]
+			}
+		}
+		out = append(out, sample)
+	}
+	ts.output = out
+
+	tail := make([][2]float64, ts.hop)
+	for i := range tail {
+		for c := 0; c < 2; c++ {
+			tail[i][c] = frame[ts.hop+i][c] * ts.window[ts.hop+i]
+		}
+	}
+	ts.tail = tail
+
+	ts.prevStart = start
+	ts.analysis += ts.speed * float64(ts.hop)
+	ts.trim()
+	return true
+}
+
+// bestStart finds the frame start within the tolerance of nominal whose
+// beginning best matches the natural continuation of the previous frame
+func (ts *TimeStretch) bestStart(nominal int) int {
+	natural := ts.prevStart + ts.hop
+	if !ts.fill(natural + ts.hop) {
+		return nominal
+	}
+	reference := ts.input[natural-ts.inputStart : natural-ts.inputStart+ts.hop]
+
+	lo := nominal - ts.tolerance
+	if lo < ts.inputStart {
+		lo = ts.inputStart
+	}
+	hi := nominal + ts.tolerance
+
+	best, bestScore := nominal, math.Inf(-1)
+	for start := lo; start <= hi; start++ {
+		candidate := ts.input[start-ts.inputStart : start-ts.inputStart+ts.hop]
+		var score float64
+		// Compare every second sample of the mono mix; plenty to find the alignment
+		for i := 0; i < ts.hop; i += 2 {
+			score += (reference[i][0] + reference[i][1]) * (candidate[i][0] + candidate[i][1])
+		}
+		if score > bestScore {
+			best, bestScore = start, score
+		}
+	}
+	return best
+}
+
+// fill reads from the source until the input reaches the absolute sample index end
+func (ts *TimeStretch) fill(end int) bool {
+	for ts.inputStart+len(ts.input) < end {
+		if ts.sourceDone {
+			return false
+		}
+		chunk := make([][2]float64, ts.frameLen)
+		n, ok := ts.source.Stream(chunk)
+		ts.input = append(ts.input, chunk[:n]...)
+		if !ok {
+			ts.sourceDone = true
+		}
+	}
+	return true
+}
+
+// trim drops input samples no later frame or search can reach
+func (ts *TimeStretch) trim() {
+	keep := int(ts.analysis) - ts.tolerance
+	if natural := ts.prevStart + ts.hop; natural < keep {
+		keep = natural
+	}
+	if drop := keep - ts.inputStart; drop > 0 && drop <= len(ts.input) {
+		ts.input = append(ts.input[:0], ts.input[drop:]...)
+		ts.inputStart = keep
+	}
+}
@@ -0,0 +1,176 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+const testSampleRate beep.SampleRate = 44100
+
+// sliceStreamer plays a slice of samples and can seek within it
+type sliceStreamer struct {
+	samples  [][2]float64
+	position int
+}
+
+func (s *sliceStreamer) Stream(samples [][2]float64) (int, bool) {
+	n := copy(samples, s.samples[s.position:])
+	s.position += n
+	return n, n > 0
+}
+
+func (s *sliceStreamer) Err() error    { return nil }
+func (s *sliceStreamer) Len() int      { return len(s.samples) }
+func (s *sliceStreamer) Position() int { return s.position }
+
+func (s *sliceStreamer) Seek(p int) error {
+	s.position = p
+	return nil
+}
+
+// sine returns n samples of a 440 Hz tone
+func sine(n int) [][2]float64 {
+	samples := make([][2]float64, n)
+	for i := range samples {
+		v := 0.5 * math.Sin(2*math.Pi*440*float64(i)/float64(testSampleRate))
+		samples[i] = [2]float64{v, v}
+	}
+	return samples
+}
+
+// ramp returns n samples whose value is their index
+func ramp(n int) [][2]float64 {
+	samples := make([][2]float64, n)
+	for i := range samples {
+		samples[i] = [2]float64{float64(i), float64(i)}
+	}
+	return samples
+}
+
+// drain streams ts until it ends and returns everything it produced
+func drain(ts *TimeStretch) [][2]float64 {
+	var out [][2]float64
+	buffer := make([][2]float64, 512)
+	for {
+		n, ok := ts.Stream(buffer)
+		out = append(out, buffer[:n]...)
+		if !ok {
+			return out
+		}
+	}
+}
+
+func TestTimeStretchPassesThroughAtNormalSpeed(t *testing.T) {
+	input := ramp(10000)
+	ts := NewTimeStretch(&sliceStreamer{samples: input}, testSampleRate, 1)
+
+	out := drain(ts)
+	if len(out) != len(input) {
+		t.Fatalf("got %d samples, want %d", len(out), len(input))
+	}
+	for i := range out {
+		if out[i] != input[i] {
+			t.Fatalf("sample %d is %v, want %v", i, out[i], input[i])
+		}
+	}
+}
+
+func TestTimeStretchLength(t *testing.T) {
+	const n = 2 * int(testSampleRate)
+	for _, speed := range []float64{0.5, 2} {
+		ts := NewTimeStretch(&sliceStreamer{samples: sine(n)}, testSampleRate, speed)
+		got := len(drain(ts))
+		want := float64(n) / speed
+		if math.Abs(float64(got)-want) > want*0.02 {
+			t.Errorf("at %gx got %d samples, want about %.0f", speed, got, want)
+		}
+	}
+}
+
+func TestTimeStretchResetAfterSeek(t *testing.T) {
+	input := sine(int(testSampleRate))
+	source := &sliceStreamer{samples: input}
+	ts := NewTimeStretch(source, testSampleRate, 2)
+
+	ts.Stream(make([][2]float64, 4096))
+	source.Seek(0)
+	ts.Reset()
+	if buffered := ts.Buffered(); buffered != 0 {
+		t.Errorf("%d samples buffered after Reset", buffered)
+	}
+
+	// The first frame after a reset starts at the new position with no previous frame to overlap
+	out := make([][2]float64, ts.hop)
+	ts.Stream(out)
+	for i := range out {
+		want := input[i][0] * ts.window[i]
+		if math.Abs(out[i][0]-want) > 1e-9 {
+			t.Fatalf("sample %d after Reset is %v, want %v", i, out[i][0], want)
+		}
+	}
+
+	got := ts.hop + len(drain(ts))
+	if want := len(input) / 2; math.Abs(float64(got-want)) > float64(want)*0.02 {
+		t.Errorf("got %d samples after Reset, want about %d", got, want)
+	}
+}
+
+func TestTimeStretchFlushesTail(t *testing.T) {
+	input := make([][2]float64, 10000)
+	for i := range input {
+		input[i] = [2]float64{1, 1}
+	}
+	ts := NewTimeStretch(&sliceStreamer{samples: input}, testSampleRate, 2)
+
+	out := drain(ts)
+	if len(out) < ts.hop {
+		t.Fatalf("got only %d samples", len(out))
+	}
+	// The stream ends with the fading second half of the last frame
+	last := out[len(out)-ts.hop:]
+	for i := range last {
+		if want := ts.window[ts.hop+i]; math.Abs(last[i][0]-want) > 1e-9 {
+			t.Fatalf("tail sample %d is %v, want %v", i, last[i][0], want)
+		}
+	}
+}
+
+func TestTimeStretchSpeedChangeKeepsInput(t *testing.T) {
+	input := ramp(int(testSampleRate))
+	source := &sliceStreamer{samples: input}
+	ts := NewTimeStretch(source, testSampleRate, 2)
+	ts.Stream(make([][2]float64, 4096))
+
+	positionBefore := source.Position() - ts.Buffered()
+	readAhead := source.Position()
+	remaining := len(ts.output)
+	ts.SetSpeed(1)
+	if positionAfter := source.Position() - ts.Buffered(); abs(positionAfter-positionBefore) > ts.frameLen {
+		t.Errorf("position jumped from %d to %d", positionBefore, positionAfter)
+	}
+
+	out := drain(ts)
+	raw := out[remaining:]
+	first := int(raw[0][0])
+	// The finished output covers about twice its length of input at 2x
+	if want := positionBefore + 2*remaining; first >= readAhead || abs(first-want) > ts.frameLen {
+		t.Errorf("pass-through resumed at sample %d, want about %d", first, want)
+	}
+	for i := range raw {
+		if int(raw[i][0]) != first+i {
+			t.Fatalf("sample %d after the speed change is %v, want %d", i, raw[i][0], first+i)
+		}
+	}
+	if last := int(raw[len(raw)-1][0]); last != len(input)-1 {
+		t.Errorf("stream ended at sample %d, want %d", last, len(input)-1)
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Bookmark is a named position in a video
+type Bookmark struct {
+	Name     string        `json:"name"`
+	Position time.Duration `json:"position"`
+}
+
+// bookmarkFile is the on-disk format of the bookmarks of one source
+type bookmarkFile struct {
+	Source    string     `json:"source"`
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
+
+// bookmarkPath returns the file holding the bookmarks of source.
+// Local files are keyed by their absolute path, URLs as they are.
+func bookmarkPath(source string) (string, string, error) {
+	dir, err := GetBookmarkDir()
+	if err != nil {
+		return "", "", err
+	}
+	key := source
+	if _, err := os.Stat(source); err == nil {
+		if abs, err := filepath.Abs(source); err == nil {
+			key = abs
+		}
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json"), key, nil
+}
+
+// LoadBookmarks returns the saved bookmarks of source sorted by position.
+// A source without saved bookmarks has none.
+func LoadBookmarks(source string) ([]Bookmark, error) {
+	path, _, err := bookmarkPath(source)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	var file bookmarkFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks in %s: %w", path, err)
+	}
+	SortBookmarks(file.Bookmarks)
+	return file.Bookmarks, nil
+}
+
+// SaveBookmarks replaces the saved bookmarks of source
+func SaveBookmarks(source string, bookmarks []Bookmark) error {
+	path, key, err := bookmarkPath(source)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(bookmarkFile{Source: key, Bookmarks: bookmarks}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated file behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	return nil
+}
+
+// SortBookmarks orders bookmarks by position
+func SortBookmarks(bookmarks []Bookmark) {
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Position < bookmarks[j].Position
+	})
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// useTempCache points the user cache directory at a new temporary directory
+func useTempCache(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+}
+
+func TestBookmarksRoundTrip(t *testing.T) {
+	useTempCache(t)
+	source := "https://www.youtube.com/watch?v=test"
+	saved := []Bookmark{
+		{Name: "chorus", Position: 90 * time.Second},
+		{Name: "intro", Position: 1500 * time.Millisecond},
+		{Name: "verse", Position: 30 * time.Second},
+	}
+	if err := SaveBookmarks(source, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadBookmarks(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Bookmark{saved[1], saved[2], saved[0]}
+	if !reflect.DeepEqual(loaded, want) {
+		t.Errorf("loaded %v, want them sorted by position: %v", loaded, want)
+	}
+
+	// Saving again replaces the bookmarks and leaves no temporary file behind
+	if err := SaveBookmarks(source, want[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := LoadBookmarks(source); !reflect.DeepEqual(loaded, want[:1]) {
+		t.Errorf("loaded %v after saving again, want %v", loaded, want[:1])
+	}
+	dir, _ := GetBookmarkDir()
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestLoadBookmarksMissing(t *testing.T) {
+	useTempCache(t)
+	bookmarks, err := LoadBookmarks("never-saved.mp4")
+	if err != nil || bookmarks != nil {
+		t.Errorf("LoadBookmarks() = %v, %v, want no bookmarks and no error", bookmarks, err)
+	}
+}
+
+func TestBookmarksPerSource(t *testing.T) {
+	useTempCache(t)
+	// A local file is keyed by its absolute path, so a relative path finds the same bookmarks
+	dir := t.TempDir()
+	video := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(video, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := SaveBookmarks(video, []Bookmark{{Name: "a", Position: time.Second}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveBookmarks("other.mp4", []Bookmark{{Name: "b", Position: 2 * time.Second}}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadBookmarks("video.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || loaded[0].Name != "a" {
+		t.Errorf("loaded %v through the relative path, want bookmark a", loaded)
+	}
+}
+
+func TestLoadBookmarksCorrupt(t *testing.T) {
+	useTempCache(t)
+	path, _, err := bookmarkPath("broken.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBookmarks("broken.mp4"); err == nil {
+		t.Error("a corrupt bookmark file loaded without an error")
+	}
+}
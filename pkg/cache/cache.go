@@ -7,9 +7,10 @@ import (
 )
 
 const (
-	appDirName = "console-cinema"
-	logDirName = "logs"
-	tmpDirName = "tmp"
+	appDirName      = "console-cinema"
+	logDirName      = "logs"
+	bookmarkDirName = "bookmarks"
+	tmpDirName      = "tmp"
 )
 
 // getAppDir returns the path to the application's base directory.
@@ -38,3 +39,16 @@ func GetLogDir() (string, error) {
 	}
 	return logPath, nil
 }
+
+// GetBookmarkDir returns the path to the directory holding saved bookmarks.
+func GetBookmarkDir() (string, error) {
+	appDir, err := getAppDir()
+	if err != nil {
+		return "", err
+	}
+	bookmarkPath := filepath.Join(appDir, bookmarkDirName)
+	if err := os.MkdirAll(bookmarkPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create bookmark directory: %w", err)
+	}
+	return bookmarkPath, nil
+}
@@ -0,0 +1,120 @@
+package cast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the asciicast format version read and written by this package
+const Version = 2
+
+// Event types of asciicast v2
+const (
+	EventOutput = "o"
+	EventInput  = "i"
+	EventResize = "r"
+	EventMarker = "m"
+)
+
+// Header is the first line of an asciicast v2 file
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is a line following the header: when it happened, its type and its data
+type Event struct {
+	Time time.Duration
+	Type string
+	Data string
+}
+
+// MarshalJSON encodes the event as [time, type, data] with the time in seconds
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Time.Seconds(), e.Type, e.Data})
+}
+
+// UnmarshalJSON decodes an event from [time, type, data]
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("event has %d fields, expected 3", len(fields))
+	}
+
+	var seconds float64
+	if err := json.Unmarshal(fields[0], &seconds); err != nil {
+		return fmt.Errorf("invalid event time: %v", err)
+	}
+	if err := json.Unmarshal(fields[1], &e.Type); err != nil {
+		return fmt.Errorf("invalid event type: %v", err)
+	}
+	if err := json.Unmarshal(fields[2], &e.Data); err != nil {
+		return fmt.Errorf("invalid event data: %v", err)
+	}
+	e.Time = time.Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// Reader reads the events of an asciicast v2 file
+type Reader struct {
+	Header Header
+
+	in   *bufio.Reader
+	line int
+}
+
+// NewReader reads the header from r
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{in: bufio.NewReader(r)}
+	line, err := reader.readLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+	if err := json.Unmarshal(line, &reader.Header); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	if reader.Header.Version != Version {
+		return nil, fmt.Errorf("unsupported asciicast version %d (only version %d is supported)", reader.Header.Version, Version)
+	}
+	return reader, nil
+}
+
+// Next returns the next event, or io.EOF after the last one
+func (r *Reader) Next() (Event, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return Event{}, err
+	}
+	var event Event
+	if err := json.Unmarshal(line, &event); err != nil {
+		return Event{}, fmt.Errorf("line %d: %v", r.line, err)
+	}
+	return event, nil
+}
+
+// readLine returns the next non-empty line. Lines are not length limited
+// since a single output event can hold a whole frame.
+func (r *Reader) readLine() ([]byte, error) {
+	for {
+		line, err := r.in.ReadBytes('\n')
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// Recorder writes an asciicast v2 file. Terminal output written with Write
+// is collected and recorded as one event on each Flush, so an event holds
+// everything drawn for one frame. Frames can also be recorded directly with
+// WriteFrame when there is no terminal.
+type Recorder struct {
+	mutex   sync.Mutex
+	file    io.WriteCloser
+	out     *bufio.Writer
+	title   string
+	started bool
+	closed  bool
+	pending []byte
+	last    time.Duration
+
+	// origin is the position of the first frame passed to WriteFrame
+	origin    time.Duration
+	hasOrigin bool
+}
+
+// Create creates the file at path and returns a Recorder writing to it
+func Create(path, title string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	return NewRecorder(file, title), nil
+}
+
+// NewRecorder returns a Recorder writing to w. The header is written by
+// Start, or by the first WriteFrame.
+func NewRecorder(w io.WriteCloser, title string) *Recorder {
+	return &Recorder{file: w, out: bufio.NewWriter(w), title: title}
+}
+
+// Start writes the header with the terminal size. Output written before Start is kept for the first event.
+func (r *Recorder) Start(width, height int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.start(width, height)
+}
+
+func (r *Recorder) start(width, height int) error {
+	if r.started {
+		return nil
+	}
+	r.started = true
+	return r.writeLine(Header{
+		Version:   Version,
+		Width:     width,
+		Height:    height,
+		Timestamp: time.Now().Unix(),
+		Title:     r.title,
+		Env:       map[string]string{"TERM": os.Getenv("TERM")},
+	})
+}
+
+// Write collects terminal output for the next event
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pending = append(r.pending, p...)
+	return len(p), nil
+}
+
+// Flush records the output collected since the previous Flush as an event at the given time
+func (r *Recorder) Flush(at time.Duration) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.flush(at)
+}
+
+func (r *Recorder) flush(at time.Duration) error {
+	if !r.started || r.closed || len(r.pending) == 0 {
+		return nil
+	}
+
+	// Keep an incomplete UTF-8 sequence for the next event so it is not mangled
+	end := len(r.pending)
+	for i := 1; i < utf8.UTFMax && i <= end; i++ {
+		if b := r.pending[end-i]; utf8.RuneStart(b) {
+			if !utf8.FullRune(r.pending[end-i:]) {
+				end -= i
+			}
+			break
+		}
+	}
+	if end == 0 {
+		return nil
+	}
+
+	err := r.event(at, EventOutput, string(r.pending[:end]))
+	r.pending = append(r.pending[:0], r.pending[end:]...)
+	return err
+}
+
+// Resize records a change of the terminal size
+func (r *Recorder) Resize(at time.Duration, width, height int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.flush(at); err != nil {
+		return err
+	}
+	return r.event(at, EventResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+// WriteFrame records a frame as a full redraw, timed by its position
+// relative to the first recorded frame. It implements video.FrameSink.
+func (r *Recorder) WriteFrame(frame *media.Frame, position time.Duration) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	data := "\x1b[H"
+	if !r.hasOrigin {
+		r.origin, r.hasOrigin = position, true
+		if err := r.start(frame.Width, frame.Height); err != nil {
+			return err
+		}
+		data = "\x1b[2J\x1b[H"
+	}
+
+	// Terminals move to the start of the next line on "\r\n", not on "\n" alone
+	data += strings.ReplaceAll(strings.TrimSuffix(frame.ANSI(), "\n"), "\n", "\r\n")
+	return r.event(position-r.origin, EventOutput, data)
+}
+
+// Close records any remaining output and closes the file. Later calls do nothing.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	err := r.flush(r.last)
+	r.closed = true
+	if flushErr := r.out.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// event writes one event line; events never go back in time
+func (r *Recorder) event(at time.Duration, eventType, data string) error {
+	if r.closed {
+		return fmt.Errorf("recording is closed")
+	}
+	if at < r.last {
+		at = r.last
+	}
+	r.last = at
+	return r.writeLine(Event{Time: at, Type: eventType, Data: data})
+}
+
+func (r *Recorder) writeLine(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := r.out.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write recording: %v", err)
+	}
+	return nil
+}
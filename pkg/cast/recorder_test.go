@@ -0,0 +1,154 @@
+package cast
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// nopCloser lets a buffer stand in for the recording file
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// readEvents reads back a finished recording
+func readEvents(t *testing.T, data []byte) (Header, []Event) {
+	t.Helper()
+	reader, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []Event
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return reader.Header, events
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := NewRecorder(nopCloser{&buffer}, "test")
+	if err := recorder.Start(80, 24); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Write([]byte("\x1b[Hhello"))
+	recorder.Flush(100 * time.Millisecond)
+	recorder.Resize(200*time.Millisecond, 100, 30)
+	recorder.Write([]byte("\"quoted\"\r\n"))
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header, events := readEvents(t, buffer.Bytes())
+	if header.Version != Version || header.Width != 80 || header.Height != 24 || header.Title != "test" {
+		t.Errorf("got header %+v", header)
+	}
+	want := []Event{
+		{Time: 100 * time.Millisecond, Type: EventOutput, Data: "\x1b[Hhello"},
+		{Time: 200 * time.Millisecond, Type: EventResize, Data: "100x30"},
+		{Time: 200 * time.Millisecond, Type: EventOutput, Data: "\"quoted\"\r\n"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d is %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestRecorderCarriesSplitRune(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := NewRecorder(nopCloser{&buffer}, "")
+	recorder.Start(80, 24)
+
+	data := []byte("a█")
+	recorder.Write(data[:2])
+	recorder.Flush(time.Second)
+	// Nothing complete is left to record
+	recorder.Write(data[2:3])
+	recorder.Flush(2 * time.Second)
+	recorder.Write(append(data[3:], 'b'))
+	recorder.Flush(3 * time.Second)
+	recorder.Close()
+
+	_, events := readEvents(t, buffer.Bytes())
+	want := []Event{
+		{Time: time.Second, Type: EventOutput, Data: "a"},
+		{Time: 3 * time.Second, Type: EventOutput, Data: "█b"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got events %+v, want %+v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d is %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestRecorderTimestampsNeverDecrease(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := NewRecorder(nopCloser{&buffer}, "")
+	recorder.Start(80, 24)
+	for _, at := range []time.Duration{time.Second, 500 * time.Millisecond, 2 * time.Second, 0} {
+		recorder.Write([]byte("x"))
+		recorder.Flush(at)
+	}
+	recorder.Close()
+
+	_, events := readEvents(t, buffer.Bytes())
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 2 * time.Second}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i := range want {
+		if events[i].Time != want[i] {
+			t.Errorf("event %d is at %v, want %v", i, events[i].Time, want[i])
+		}
+	}
+}
+
+func TestRecorderWriteFrame(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := NewRecorder(nopCloser{&buffer}, "")
+	frame := media.NewFrame(2, 2)
+	frame.Set(0, 0, media.Cell{Rune: 'a'})
+	frame.Set(1, 1, media.Cell{Rune: 'b'})
+
+	recorder.WriteFrame(frame, 5*time.Second)
+	recorder.WriteFrame(frame, 6*time.Second)
+	recorder.Close()
+
+	header, events := readEvents(t, buffer.Bytes())
+	if header.Width != 2 || header.Height != 2 {
+		t.Errorf("header is %dx%d, want the frame size 2x2", header.Width, header.Height)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	// Frames are timed from the first one
+	if events[0].Time != 0 || events[1].Time != time.Second {
+		t.Errorf("events are at %v and %v, want 0s and 1s", events[0].Time, events[1].Time)
+	}
+
+	term := NewTerminal(2, 2)
+	for _, event := range events {
+		term.WriteString(event.Data)
+	}
+	if got := term.Frame().PlainText(); got != "a \n b\n" {
+		t.Errorf("replayed %q, want %q", got, "a \n b\n")
+	}
+}
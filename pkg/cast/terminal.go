@@ -0,0 +1,359 @@
+package cast
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// parserState is where the terminal parser is within an escape sequence
+type parserState int
+
+const (
+	stateGround parserState = iota
+	stateEscape
+	stateCharset
+	stateCSI
+	stateString
+	stateStringEscape
+)
+
+// Terminal is a minimal virtual terminal that turns recorded output back into
+// a grid of cells. It understands the sequences tcell and the recorder emit:
+// cursor movement, erasing, scrolling at the bottom line and SGR colors and
+// attributes. Everything else is ignored.
+type Terminal struct {
+	frame *media.Frame
+
+	x, y int
+	// wrapPending defers wrapping after the last column until the next character, like xterm
+	wrapPending bool
+	style       media.Cell
+	savedX      int
+	savedY      int
+
+	state  parserState
+	params []byte
+	// partial holds the start of a UTF-8 sequence split across writes
+	partial []byte
+}
+
+// NewTerminal creates a blank terminal of the given size
+func NewTerminal(width, height int) *Terminal {
+	t := &Terminal{}
+	t.Resize(width, height)
+	return t
+}
+
+// Size returns the size of the terminal in cells
+func (t *Terminal) Size() (int, int) {
+	return t.frame.Width, t.frame.Height
+}
+
+// Resize changes the size of the terminal, keeping the cells that still fit
+func (t *Terminal) Resize(width, height int) {
+	width, height = max(width, 1), max(height, 1)
+	frame := media.NewFrame(width, height)
+	if t.frame != nil {
+		for y := 0; y < min(height, t.frame.Height); y++ {
+			copy(frame.Row(y), t.frame.Row(y)[:min(width, t.frame.Width)])
+		}
+	}
+	t.frame = frame
+	t.x, t.y = min(t.x, width-1), min(t.y, height-1)
+	t.wrapPending = false
+}
+
+// Reset clears the screen and every mode, as after a terminal reset
+func (t *Terminal) Reset() {
+	width, height := t.Size()
+	*t = Terminal{}
+	t.Resize(width, height)
+}
+
+// Frame returns a copy of the current screen
+func (t *Terminal) Frame() *media.Frame {
+	return t.frame.Clone()
+}
+
+// Write feeds terminal output to the parser
+func (t *Terminal) Write(p []byte) (int, error) {
+	data := p
+	if len(t.partial) > 0 {
+		data = append(t.partial, p...)
+		t.partial = nil
+	}
+
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			t.partial = append([]byte(nil), data...)
+			break
+		}
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		t.handle(r)
+	}
+	return len(p), nil
+}
+
+// WriteString feeds terminal output to the parser
+func (t *Terminal) WriteString(s string) {
+	t.Write([]byte(s))
+}
+
+func (t *Terminal) handle(r rune) {
+	switch t.state {
+	case stateEscape:
+		t.handleEscape(r)
+	case stateCharset:
+		// The character set designation is ignored
+		t.state = stateGround
+	case stateCSI:
+		if r >= 0x40 && r <= 0x7e {
+			t.handleCSI(r, string(t.params))
+			t.state = stateGround
+		} else {
+			t.params = append(t.params, byte(r))
+		}
+	case stateString:
+		// OSC and DCS strings end with BEL or ST (ESC \)
+		if r == 0x07 {
+			t.state = stateGround
+		} else if r == 0x1b {
+			t.state = stateStringEscape
+		}
+	case stateStringEscape:
+		t.state = stateGround
+	default:
+		t.handleGround(r)
+	}
+}
+
+func (t *Terminal) handleGround(r rune) {
+	width, height := t.Size()
+	switch r {
+	case 0x1b:
+		t.state = stateEscape
+	case '\r':
+		t.x, t.wrapPending = 0, false
+	case '\n', '\v', '\f':
+		t.lineFeed()
+	case '\b':
+		if t.x > 0 {
+			t.x--
+		}
+		t.wrapPending = false
+	case '\t':
+		t.x = min((t.x/8+1)*8, width-1)
+	default:
+		if r < 0x20 || r == 0x7f {
+			return
+		}
+		if t.wrapPending {
+			t.x, t.wrapPending = 0, false
+			t.lineFeed()
+		}
+		cell := t.style
+		cell.Rune = r
+		t.frame.Set(t.x, min(t.y, height-1), cell)
+		if t.x == width-1 {
+			t.wrapPending = true
+		} else {
+			t.x++
+		}
+	}
+}
+
+func (t *Terminal) handleEscape(r rune) {
+	t.state = stateGround
+	switch r {
+	case '[':
+		t.state = stateCSI
+		t.params = t.params[:0]
+	case ']', 'P', '_', '^':
+		t.state = stateString
+	case '(', ')', '*', '+':
+		t.state = stateCharset
+	case '7':
+		t.savedX, t.savedY = t.x, t.y
+	case '8':
+		t.x, t.y, t.wrapPending = t.savedX, t.savedY, false
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.x = 0
+		t.lineFeed()
+	case 'c':
+		t.Reset()
+	}
+}
+
+func (t *Terminal) handleCSI(final rune, params string) {
+	if strings.HasPrefix(params, "?") || strings.HasPrefix(params, ">") {
+		// Private modes such as the alternate screen and cursor visibility
+		return
+	}
+	args := strings.Split(params, ";")
+	arg := func(i, fallback int) int {
+		if i >= len(args) {
+			return fallback
+		}
+		n, err := strconv.Atoi(strings.SplitN(args[i], ":", 2)[0])
+		if err != nil || n == 0 {
+			return fallback
+		}
+		return n
+	}
+
+	width, height := t.Size()
+	t.wrapPending = false
+	switch final {
+	case 'H', 'f':
+		t.x, t.y = clamp(arg(1, 1)-1, 0, width-1), clamp(arg(0, 1)-1, 0, height-1)
+	case 'A':
+		t.y = max(t.y-arg(0, 1), 0)
+	case 'B', 'e':
+		t.y = min(t.y+arg(0, 1), height-1)
+	case 'C', 'a':
+		t.x = min(t.x+arg(0, 1), width-1)
+	case 'D':
+		t.x = max(t.x-arg(0, 1), 0)
+	case 'E':
+		t.x, t.y = 0, min(t.y+arg(0, 1), height-1)
+	case 'F':
+		t.x, t.y = 0, max(t.y-arg(0, 1), 0)
+	case 'G', '`':
+		t.x = clamp(arg(0, 1)-1, 0, width-1)
+	case 'd':
+		t.y = clamp(arg(0, 1)-1, 0, height-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			t.erase(t.x, t.y, width-1, height-1)
+		case 1:
+			t.erase(0, 0, t.x, t.y)
+		default:
+			t.erase(0, 0, width-1, height-1)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			t.erase(t.x, t.y, width-1, t.y)
+		case 1:
+			t.erase(0, t.y, t.x, t.y)
+		default:
+			t.erase(0, t.y, width-1, t.y)
+		}
+	case 'X':
+		t.erase(t.x, t.y, min(t.x+arg(0, 1)-1, width-1), t.y)
+	case 's':
+		t.savedX, t.savedY = t.x, t.y
+	case 'u':
+		t.x, t.y = t.savedX, t.savedY
+	case 'm':
+		t.handleSGR(args)
+	}
+}
+
+// handleSGR applies Select Graphic Rendition parameters to the current style.
+// Extended colors are accepted both as "38;2;r;g;b" and as "38:2::r:g:b".
+func (t *Terminal) handleSGR(args []string) {
+	for i := 0; i < len(args); i++ {
+		sub := strings.Split(args[i], ":")
+		code, _ := strconv.Atoi(sub[0])
+		switch {
+		case code == 0:
+			t.style = media.Cell{}
+		case code == 1:
+			t.style.Attrs |= media.AttrBold
+		case code == 22:
+			t.style.Attrs &^= media.AttrBold
+		case code == 7:
+			t.style.Attrs |= media.AttrReverse
+		case code == 27:
+			t.style.Attrs &^= media.AttrReverse
+		case code >= 30 && code <= 37:
+			t.style.Fg = media.NewIndexColor(code - 30)
+		case code >= 90 && code <= 97:
+			t.style.Fg = media.NewIndexColor(code - 90 + 8)
+		case code == 39:
+			t.style.Fg = media.ColorDefault
+		case code >= 40 && code <= 47:
+			t.style.Bg = media.NewIndexColor(code - 40)
+		case code >= 100 && code <= 107:
+			t.style.Bg = media.NewIndexColor(code - 100 + 8)
+		case code == 49:
+			t.style.Bg = media.ColorDefault
+		case code == 38 || code == 48:
+			colon := len(sub) > 1
+			values := args[i+1:]
+			if colon {
+				values = sub[1:]
+			}
+			color, used := parseExtendedColor(values, colon)
+			if !colon {
+				i += used
+			}
+			if code == 38 {
+				t.style.Fg = color
+			} else {
+				t.style.Bg = color
+			}
+		}
+	}
+}
+
+// parseExtendedColor parses the values following 38 or 48 and returns the
+// color and the number of values it used. colon tells the values came as
+// colon separated sub-parameters.
+func parseExtendedColor(values []string, colon bool) (media.Color, int) {
+	number := func(i int) int {
+		if i >= len(values) {
+			return 0
+		}
+		n, _ := strconv.Atoi(values[i])
+		return n
+	}
+
+	switch number(0) {
+	case 5:
+		return media.NewIndexColor(number(1)), 2
+	case 2:
+		// The colon form may carry a color space id before the components
+		offset := 1
+		if colon && len(values) >= 5 {
+			offset = 2
+		}
+		return media.NewRGBColor(uint8(number(offset)), uint8(number(offset+1)), uint8(number(offset+2))), 4
+	default:
+		return media.ColorDefault, 1
+	}
+}
+
+// lineFeed moves the cursor down, scrolling the screen up at the bottom line
+func (t *Terminal) lineFeed() {
+	t.wrapPending = false
+	_, height := t.Size()
+	if t.y < height-1 {
+		t.y++
+		return
+	}
+	copy(t.frame.Cells, t.frame.Cells[t.frame.Width:])
+	t.erase(0, height-1, t.frame.Width-1, height-1)
+}
+
+// erase blanks the cells from (x0, y0) to (x1, y1) in reading order with the current background
+func (t *Terminal) erase(x0, y0, x1, y1 int) {
+	blank := media.Cell{Rune: ' ', Bg: t.style.Bg}
+	width := t.frame.Width
+	for i := y0*width + x0; i <= y1*width+x1 && i < len(t.frame.Cells); i++ {
+		t.frame.Cells[i] = blank
+	}
+}
+
+// clamp limits v to the range [lo, hi]
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,115 @@
+package cast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// rows returns the text of each row of the terminal
+func rows(t *Terminal) []string {
+	return strings.Split(strings.TrimSuffix(t.Frame().PlainText(), "\n"), "\n")
+}
+
+func TestTerminalText(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"plain", "ab\r\ncd", []string{"ab  ", "cd  ", "    "}},
+		{"cursor position", "\x1b[2;3Hx\x1b[1;1Hy", []string{"y   ", "  x ", "    "}},
+		{"cursor position defaults", "\x1b[3;4Hx\x1b[Hy", []string{"y   ", "    ", "   x"}},
+		{"cursor position clamped", "\x1b[9;9Hx", []string{"    ", "    ", "   x"}},
+		// Writing at the last column leaves the cursor there
+		{"relative moves", "\x1b[2B\x1b[3Cx\x1b[2A\x1b[2Dy", []string{" y  ", "    ", "   x"}},
+		{"column and row", "\x1b[3Gx\x1b[2dy", []string{"  x ", "   y", "    "}},
+		{"erase to end of screen", "abcd\r\nefgh\r\nijkl\x1b[2;3H\x1b[J", []string{"abcd", "ef  ", "    "}},
+		{"erase to start of screen", "abcd\r\nefgh\r\nijkl\x1b[2;3H\x1b[1J", []string{"    ", "   h", "ijkl"}},
+		{"erase screen", "abcd\r\nefgh\x1b[2J", []string{"    ", "    ", "    "}},
+		{"erase to end of line", "abcd\r\nefgh\x1b[1;2H\x1b[K", []string{"a   ", "efgh", "    "}},
+		{"erase to start of line", "abcd\x1b[1;2H\x1b[1K", []string{"  cd", "    ", "    "}},
+		{"erase line", "abcd\x1b[1;2H\x1b[2K", []string{"    ", "    ", "    "}},
+		{"erase characters", "abcd\x1b[1;2H\x1b[2X", []string{"a  d", "    ", "    "}},
+		{"wrap is pending at the last column", "abcd\x1b[1Gx", []string{"xbcd", "    ", "    "}},
+		{"wrap on the next character", "abcde", []string{"abcd", "e   ", "    "}},
+		{"carriage return cancels the wrap", "abcd\rx", []string{"xbcd", "    ", "    "}},
+		{"scroll at the bottom line", "a\r\nb\r\nc\r\nd", []string{"b   ", "c   ", "d   "}},
+		{"scroll on wrap", "\x1b[3;1Habcdef", []string{"    ", "abcd", "ef  "}},
+		{"save and restore", "\x1b[2;2H\x1b7\x1b[1;1Hx\x1b8y", []string{"x   ", " y  ", "    "}},
+		{"private modes and strings are ignored", "\x1b[?1049h\x1b]0;title\x07\x1b(Bab", []string{"ab  ", "    ", "    "}},
+		{"reset", "ab\x1bcx", []string{"x   ", "    ", "    "}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			term := NewTerminal(4, 3)
+			term.WriteString(test.output)
+			got := rows(term)
+			for y := range test.want {
+				if got[y] != test.want[y] {
+					t.Fatalf("got rows %q, want %q", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTerminalSGR(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   media.Cell
+	}{
+		{"default", "x", media.Cell{Rune: 'x'}},
+		{"basic colors", "\x1b[31;42mx", media.Cell{Rune: 'x', Fg: media.NewIndexColor(1), Bg: media.NewIndexColor(2)}},
+		{"bright colors", "\x1b[91;102mx", media.Cell{Rune: 'x', Fg: media.NewIndexColor(9), Bg: media.NewIndexColor(10)}},
+		{"256 colors", "\x1b[38;5;196;48;5;21mx", media.Cell{Rune: 'x', Fg: media.NewIndexColor(196), Bg: media.NewIndexColor(21)}},
+		{"RGB", "\x1b[38;2;1;2;3;48;2;4;5;6mx", media.Cell{Rune: 'x', Fg: media.NewRGBColor(1, 2, 3), Bg: media.NewRGBColor(4, 5, 6)}},
+		{"RGB with colons", "\x1b[38:2::1:2:3mx", media.Cell{Rune: 'x', Fg: media.NewRGBColor(1, 2, 3)}},
+		{"RGB followed by bold", "\x1b[38;2;1;2;3;1mx", media.Cell{Rune: 'x', Fg: media.NewRGBColor(1, 2, 3), Attrs: media.AttrBold}},
+		{"attributes", "\x1b[1;7mx", media.Cell{Rune: 'x', Attrs: media.AttrBold | media.AttrReverse}},
+		{"attributes off", "\x1b[1;7m\x1b[22;27mx", media.Cell{Rune: 'x'}},
+		{"default colors", "\x1b[31;42m\x1b[39;49mx", media.Cell{Rune: 'x'}},
+		{"reset", "\x1b[1;31;42m\x1b[mx", media.Cell{Rune: 'x'}},
+		{"erase uses the background", "\x1b[44m\x1b[K", media.Cell{Rune: ' ', Bg: media.NewIndexColor(4)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			term := NewTerminal(4, 1)
+			term.WriteString(test.output)
+			if got := term.Frame().At(0, 0); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTerminalSplitRune(t *testing.T) {
+	term := NewTerminal(4, 1)
+	data := []byte("a█b")
+	term.Write(data[:2])
+	term.Write(data[2:3])
+	term.Write(data[3:])
+	if got := rows(term)[0]; got != "a█b " {
+		t.Errorf("got %q, want %q", got, "a█b ")
+	}
+}
+
+func TestTerminalResize(t *testing.T) {
+	term := NewTerminal(4, 2)
+	term.WriteString("abcd\r\nefgh")
+	term.Resize(2, 3)
+	want := []string{"ab", "ef", "  "}
+	got := rows(term)
+	for y := range want {
+		if got[y] != want[y] {
+			t.Fatalf("got rows %q, want %q", got, want)
+		}
+	}
+	// The cursor was moved back inside the screen
+	term.WriteString("x")
+	if got := rows(term)[1]; got != "ex" {
+		t.Errorf("got %q after writing at the clamped cursor, want %q", got, "ex")
+	}
+}
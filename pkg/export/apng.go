@@ -0,0 +1,188 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"time"
+)
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// actlOffset is where the acTL chunk starts: after the signature and the IHDR chunk
+const actlOffset = 8 + 12 + 13
+
+// apngEncoder writes animated PNGs. Each frame is encoded with image/png and
+// its IDAT chunks are reused: as is for the first frame and as fdAT chunks
+// for the others. Frames are written as they arrive; the frame count in
+// the acTL chunk near the start of the file is filled in by Close.
+type apngEncoder struct {
+	path     string
+	file     *os.File
+	out      *bufio.Writer
+	header   []byte
+	width    int
+	height   int
+	frames   int
+	sequence uint32
+}
+
+func newAPNGEncoder(path string) *apngEncoder {
+	return &apngEncoder{path: path}
+}
+
+// WriteFrame implements Encoder
+func (e *apngEncoder) WriteFrame(img *image.RGBA, delay time.Duration) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode frame: %v", err)
+	}
+
+	var header []byte
+	var idat [][]byte
+	data := buf.Bytes()[len(pngSignature):]
+	for len(data) >= 12 {
+		length := binary.BigEndian.Uint32(data)
+		chunkType := string(data[4:8])
+		payload := data[8 : 8+length]
+		data = data[12+length:]
+
+		switch chunkType {
+		case "IHDR":
+			header = payload
+		case "IDAT":
+			idat = append(idat, payload)
+		}
+	}
+
+	if e.file == nil {
+		e.width, e.height = img.Bounds().Dx(), img.Bounds().Dy()
+		if err := e.create(header); err != nil {
+			return err
+		}
+	} else if !bytes.Equal(e.header, header) {
+		return fmt.Errorf("frame layout changed from the first frame")
+	}
+	if err := e.writeFrame(idat, delay); err != nil {
+		return fmt.Errorf("failed to write %s: %v", e.path, err)
+	}
+	return nil
+}
+
+// Close implements Encoder
+func (e *apngEncoder) Close() error {
+	if e.file == nil {
+		return fmt.Errorf("no frames to write to %s", e.path)
+	}
+	defer e.file.Close()
+
+	if err := writeChunk(e.out, "IEND", nil); err != nil {
+		return fmt.Errorf("failed to write %s: %v", e.path, err)
+	}
+	if err := e.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", e.path, err)
+	}
+
+	// Now that the frames are counted, rewrite the acTL chunk in place
+	var actl bytes.Buffer
+	writeChunk(&actl, "acTL", e.animationControl())
+	if _, err := e.file.WriteAt(actl.Bytes(), actlOffset); err != nil {
+		return fmt.Errorf("failed to write %s: %v", e.path, err)
+	}
+	return e.file.Close()
+}
+
+// create creates the file and writes the signature, the header and an acTL
+// chunk counting no frames yet
+func (e *apngEncoder) create(header []byte) error {
+	if len(header) != 13 {
+		return fmt.Errorf("failed to encode frame: unexpected PNG header")
+	}
+	file, err := os.Create(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", e.path, err)
+	}
+	e.file, e.out, e.header = file, bufio.NewWriter(file), header
+
+	e.out.Write(pngSignature)
+	writeChunk(e.out, "IHDR", e.header)
+	return writeChunk(e.out, "acTL", e.animationControl())
+}
+
+// animationControl builds an acTL chunk: the frame count, then 0 plays for an endless loop
+func (e *apngEncoder) animationControl() []byte {
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl, uint32(e.frames))
+	return actl
+}
+
+// writeFrame writes the fcTL chunk of a frame followed by its image data
+func (e *apngEncoder) writeFrame(idat [][]byte, delay time.Duration) error {
+	if err := writeChunk(e.out, "fcTL", e.frameControl(e.sequence, delay)); err != nil {
+		return err
+	}
+	e.sequence++
+
+	for _, data := range idat {
+		if e.frames == 0 {
+			// The first frame doubles as the still image shown by plain PNG viewers
+			if err := writeChunk(e.out, "IDAT", data); err != nil {
+				return err
+			}
+			continue
+		}
+		fdat := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, e.sequence)
+		copy(fdat[4:], data)
+		if err := writeChunk(e.out, "fdAT", fdat); err != nil {
+			return err
+		}
+		e.sequence++
+	}
+	e.frames++
+	return nil
+}
+
+// frameControl builds an fcTL chunk covering the whole canvas for the given delay
+func (e *apngEncoder) frameControl(sequence uint32, delay time.Duration) []byte {
+	milliseconds := delay.Milliseconds()
+	if milliseconds > 0xffff {
+		milliseconds = 0xffff
+	}
+
+	fctl := make([]byte, 26)
+	binary.BigEndian.PutUint32(fctl[0:], sequence)
+	binary.BigEndian.PutUint32(fctl[4:], uint32(e.width))
+	binary.BigEndian.PutUint32(fctl[8:], uint32(e.height))
+	// The x and y offsets at [12:20] stay zero
+	binary.BigEndian.PutUint16(fctl[20:], uint16(milliseconds))
+	binary.BigEndian.PutUint16(fctl[22:], 1000)
+	// Dispose and blend operations at [24:26] stay zero: keep the frame and replace the canvas
+	return fctl
+}
+
+// writeChunk writes a PNG chunk with its length and CRC
+func writeChunk(w io.Writer, chunkType string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], chunkType)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	footer := binary.BigEndian.AppendUint32(nil, crc.Sum32())
+
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type pngChunk struct {
+	kind string
+	data []byte
+}
+
+// readChunks splits a PNG file into chunks, checking their CRCs
+func readChunks(t *testing.T, data []byte) []pngChunk {
+	t.Helper()
+	if !bytes.HasPrefix(data, pngSignature) {
+		t.Fatal("missing PNG signature")
+	}
+	data = data[len(pngSignature):]
+	var chunks []pngChunk
+	for len(data) > 0 {
+		if len(data) < 12 {
+			t.Fatalf("truncated chunk of %d bytes", len(data))
+		}
+		length := binary.BigEndian.Uint32(data)
+		chunk := pngChunk{kind: string(data[4:8]), data: data[8 : 8+length]}
+		if crc := binary.BigEndian.Uint32(data[8+length:]); crc != crc32.ChecksumIEEE(data[4:8+length]) {
+			t.Errorf("bad CRC on %s chunk", chunk.kind)
+		}
+		chunks = append(chunks, chunk)
+		data = data[12+length:]
+	}
+	return chunks
+}
+
+func TestAPNGEncoder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.png")
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+	encoder := newAPNGEncoder(path)
+	delays := []time.Duration{40 * time.Millisecond, 100 * time.Millisecond, time.Second}
+	for i, delay := range delays {
+		c := red
+		if i%2 == 1 {
+			c = blue
+		}
+		if err := encoder.WriteFrame(testFrame(6, 4, c, white), delay); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := readChunks(t, data)
+	if chunks[0].kind != "IHDR" || chunks[1].kind != "acTL" || chunks[len(chunks)-1].kind != "IEND" {
+		t.Fatalf("unexpected chunk order starting %s, %s", chunks[0].kind, chunks[1].kind)
+	}
+	if frames := binary.BigEndian.Uint32(chunks[1].data); frames != uint32(len(delays)) {
+		t.Errorf("acTL counts %d frames, want %d", frames, len(delays))
+	}
+
+	var fctl [][]byte
+	sequence := uint32(0)
+	for _, chunk := range chunks {
+		switch chunk.kind {
+		case "fcTL", "fdAT":
+			if got := binary.BigEndian.Uint32(chunk.data); got != sequence {
+				t.Errorf("%s has sequence number %d, want %d", chunk.kind, got, sequence)
+			}
+			sequence++
+			if chunk.kind == "fcTL" {
+				fctl = append(fctl, chunk.data)
+			}
+		}
+	}
+	if len(fctl) != len(delays) {
+		t.Fatalf("got %d fcTL chunks, want %d", len(fctl), len(delays))
+	}
+	for i, delay := range delays {
+		numerator, denominator := binary.BigEndian.Uint16(fctl[i][20:]), binary.BigEndian.Uint16(fctl[i][22:])
+		if got := time.Duration(numerator) * time.Second / time.Duration(denominator); got != delay {
+			t.Errorf("frame %d is shown for %v, want %v", i, got, delay)
+		}
+	}
+
+	// Plain PNG decoders show the first frame
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("the first frame does not decode: %v", err)
+	}
+	if got := color.RGBAModel.Convert(img.At(3, 2)); got != red {
+		t.Errorf("first frame pixel is %v, want %v", got, red)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != white {
+		t.Errorf("first frame corner is %v, want %v", got, white)
+	}
+}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"image"
+	"time"
+)
+
+// Encoder writes rasterized frames to an animation or video file.
+// Every frame must have the size of the first one.
+type Encoder interface {
+	// WriteFrame adds a frame shown for the given delay
+	WriteFrame(img *image.RGBA, delay time.Duration) error
+	// Close finishes the file
+	Close() error
+}
+
+// NewEncoder creates an encoder writing format to path. fps is the nominal
+// frame rate, used where the container needs a fixed one, and dither
+// enables error diffusion when colors are reduced to a palette.
+func NewEncoder(format Format, path string, fps float64, dither bool) (Encoder, error) {
+	switch format {
+	case FormatGIF:
+		return newGIFEncoder(path, dither), nil
+	case FormatAPNG:
+		return newAPNGEncoder(path), nil
+	case FormatMP4:
+		return newVideoEncoder(path, fps), nil
+	default:
+		_, err := ParseFormat(string(format))
+		return nil, err
+	}
+}
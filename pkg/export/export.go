@@ -0,0 +1,125 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/video"
+)
+
+// Options selects which frames Export writes and how they are drawn
+type Options struct {
+	Format Format
+	Path   string
+	// FPS is the output frame rate; zero keeps every frame of the video
+	FPS float64
+	// Start is the position of the first frame; End stops exporting when
+	// non-zero, and Frames limits the number of frames when non-zero
+	Start  time.Duration
+	End    time.Duration
+	Frames int
+	// FontSize is the cell height in pixels; zero uses the size of the bundled font
+	FontSize int
+	// Dither enables error diffusion when a format reduces colors to a palette
+	Dither bool
+	// Quantizer maps frame colors onto a terminal palette before drawing; nil keeps them as they are
+	Quantizer *media.Quantizer
+}
+
+// Export converts the frames of the video player, draws them as they would
+// appear in a terminal and encodes them to options.Path. Each frame is shown
+// until the timestamp of the next one. It returns the number of frames written.
+func Export(player *video.VideoPlayer, options Options) (int, error) {
+	if options.End > 0 && options.End <= options.Start {
+		return 0, fmt.Errorf("end %v must be after start %v", options.End, options.Start)
+	}
+
+	sourceInterval := time.Duration(float64(time.Second) / player.GetFPS())
+	interval := sourceInterval
+	fps := player.GetFPS()
+	if options.FPS > 0 {
+		interval = time.Duration(float64(time.Second) / options.FPS)
+		fps = options.FPS
+	}
+
+	encoder, err := NewEncoder(options.Format, options.Path, fps, options.Dither)
+	if err != nil {
+		return 0, err
+	}
+	if options.Start > 0 {
+		player.SeekTo(options.Start)
+	}
+
+	rasterizer := media.NewRasterizer(options.FontSize)
+	var (
+		pending    *image.RGBA
+		pendingPTS time.Duration
+		bounds     image.Rectangle
+		next       = options.Start
+		taken      int
+		written    int
+	)
+	for options.Frames <= 0 || taken < options.Frames {
+		frame, err := player.GetNextFrame()
+		if err != nil {
+			if pending == nil {
+				encoder.Close()
+				return 0, fmt.Errorf("no frames to export: %v", err)
+			}
+			// The end of the video ends the export
+			break
+		}
+		pts := player.GetPosition()
+		if options.End > 0 && pts >= options.End {
+			break
+		}
+		// Sample the video at the output frame rate
+		if options.FPS > 0 && pts < next-sourceInterval/2 {
+			continue
+		}
+		for next <= pts {
+			next += interval
+		}
+
+		if options.Quantizer != nil {
+			frame = options.Quantizer.QuantizeFrame(frame)
+		}
+		img := rasterizer.Draw(frame)
+		if pending == nil {
+			bounds = img.Bounds()
+		} else if img.Bounds() != bounds {
+			img = fitCanvas(img, bounds)
+		}
+
+		if pending != nil {
+			if err := encoder.WriteFrame(pending, pts-pendingPTS); err != nil {
+				encoder.Close()
+				return written, err
+			}
+			written++
+		}
+		pending, pendingPTS = img, pts
+		taken++
+	}
+
+	if pending != nil {
+		if err := encoder.WriteFrame(pending, interval); err != nil {
+			encoder.Close()
+			return written, err
+		}
+		written++
+	}
+	return written, encoder.Close()
+}
+
+// fitCanvas draws img onto a black canvas of the given bounds, so frames
+// converted after a size change still match the first frame
+func fitCanvas(img *image.RGBA, bounds image.Rectangle) *image.RGBA {
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, image.Black, image.Point{}, draw.Src)
+	draw.Draw(canvas, bounds, img, image.Point{}, draw.Src)
+	return canvas
+}
@@ -0,0 +1,43 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the container an export is encoded to
+type Format string
+
+const (
+	FormatGIF  Format = "gif"
+	FormatAPNG Format = "apng"
+	FormatMP4  Format = "mp4"
+)
+
+// Formats lists every supported export format
+var Formats = []Format{FormatGIF, FormatAPNG, FormatMP4}
+
+// ParseFormat converts a string to a Format
+func ParseFormat(s string) (Format, error) {
+	for _, format := range Formats {
+		if Format(s) == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q (available: %v)", s, Formats)
+}
+
+// FormatFromPath picks the format matching the extension of path
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gif":
+		return FormatGIF, nil
+	case ".png", ".apng":
+		return FormatAPNG, nil
+	case ".mp4":
+		return FormatMP4, nil
+	default:
+		return "", fmt.Errorf("cannot tell the export format from %q; use --format (available: %v)", path, Formats)
+	}
+}
@@ -0,0 +1,192 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/lzw"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"os"
+	"time"
+)
+
+// gifDelayUnit is the resolution of GIF frame delays
+const gifDelayUnit = 10 * time.Millisecond
+
+// minGIFDelay is the shortest delay browsers honor; shorter ones are slowed down to 100 ms
+const minGIFDelay = 2
+
+// gifEncoder writes each frame to the file as it arrives, so only the
+// frame being encoded is held in memory. image/gif can only encode a
+// complete animation, so the blocks are written here.
+type gifEncoder struct {
+	path   string
+	dither bool
+	file   *os.File
+	out    *bufio.Writer
+	bounds image.Rectangle
+	// pixels is reused to compress each frame
+	pixels bytes.Buffer
+
+	// elapsed and written are the total requested and written delays, so
+	// rounding to hundredths of a second does not add up over a long clip
+	elapsed time.Duration
+	written int
+}
+
+func newGIFEncoder(path string, dither bool) *gifEncoder {
+	return &gifEncoder{path: path, dither: dither}
+}
+
+// WriteFrame implements Encoder
+func (e *gifEncoder) WriteFrame(img *image.RGBA, delay time.Duration) error {
+	e.elapsed += delay
+	frameDelay := int((e.elapsed+gifDelayUnit/2)/gifDelayUnit) - e.written
+	if frameDelay < minGIFDelay {
+		frameDelay = minGIFDelay
+	}
+	e.written += frameDelay
+
+	if e.file == nil {
+		if err := e.create(img.Bounds()); err != nil {
+			return err
+		}
+	} else if img.Bounds() != e.bounds {
+		return fmt.Errorf("frame layout changed from the first frame")
+	}
+	if err := e.writeImage(e.paletted(img), frameDelay); err != nil {
+		return fmt.Errorf("failed to write %s: %v", e.path, err)
+	}
+	return nil
+}
+
+// Close implements Encoder
+func (e *gifEncoder) Close() error {
+	if e.file == nil {
+		return fmt.Errorf("no frames to write to %s", e.path)
+	}
+	defer e.file.Close()
+
+	e.out.WriteByte(0x3b) // Trailer
+	if err := e.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", e.path, err)
+	}
+	return e.file.Close()
+}
+
+// create creates the file and writes the header, the logical screen without
+// a global color table and an endless loop
+func (e *gifEncoder) create(bounds image.Rectangle) error {
+	file, err := os.Create(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", e.path, err)
+	}
+	e.file, e.out, e.bounds = file, bufio.NewWriter(file), bounds
+
+	e.out.WriteString("GIF89a")
+	e.out.Write(binary.LittleEndian.AppendUint16(nil, uint16(bounds.Dx())))
+	e.out.Write(binary.LittleEndian.AppendUint16(nil, uint16(bounds.Dy())))
+	e.out.Write([]byte{0x00, 0x00, 0x00})
+	// NETSCAPE2.0 application extension with a loop count of 0, looping forever
+	e.out.Write([]byte{0x21, 0xff, 0x0b})
+	e.out.WriteString("NETSCAPE2.0")
+	_, err = e.out.Write([]byte{0x03, 0x01, 0x00, 0x00, 0x00})
+	return err
+}
+
+// writeImage writes a graphic control extension with the delay, followed by
+// the image with its own color table
+func (e *gifEncoder) writeImage(img *image.Paletted, delay int) error {
+	// The color table holds a power of two colors, at least 2
+	bits := 1
+	for 1<<bits < len(img.Palette) {
+		bits++
+	}
+
+	e.out.Write([]byte{0x21, 0xf9, 0x04, 0x00})
+	e.out.Write(binary.LittleEndian.AppendUint16(nil, uint16(delay)))
+	e.out.Write([]byte{0x00, 0x00})
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	e.out.Write([]byte{0x2c, 0x00, 0x00, 0x00, 0x00})
+	e.out.Write(binary.LittleEndian.AppendUint16(nil, uint16(width)))
+	e.out.Write(binary.LittleEndian.AppendUint16(nil, uint16(height)))
+	e.out.WriteByte(0x80 | byte(bits-1))
+	table := make([]byte, 3<<bits)
+	for i, c := range img.Palette {
+		r, g, b, _ := c.RGBA()
+		table[3*i], table[3*i+1], table[3*i+2] = byte(r>>8), byte(g>>8), byte(b>>8)
+	}
+	e.out.Write(table)
+
+	// The pixels are LZW compressed and split into blocks of at most 255 bytes
+	litWidth := max(bits, 2)
+	e.pixels.Reset()
+	compressor := lzw.NewWriter(&e.pixels, lzw.LSB, litWidth)
+	for y := 0; y < height; y++ {
+		if _, err := compressor.Write(img.Pix[y*img.Stride : y*img.Stride+width]); err != nil {
+			return err
+		}
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+	e.out.WriteByte(byte(litWidth))
+	data := e.pixels.Bytes()
+	for len(data) > 0 {
+		n := min(len(data), 255)
+		e.out.WriteByte(byte(n))
+		e.out.Write(data[:n])
+		data = data[n:]
+	}
+	return e.out.WriteByte(0x00)
+}
+
+// paletted converts img to a paletted image. Frames with at most 256 colors,
+// which is common for terminal art, keep their exact colors; others are
+// mapped onto a fixed palette.
+func (e *gifEncoder) paletted(img *image.RGBA) *image.Paletted {
+	colors := exactPalette(img, 256)
+	if colors == nil {
+		out := image.NewPaletted(img.Bounds(), palette.Plan9)
+		if e.dither {
+			draw.FloydSteinberg.Draw(out, img.Bounds(), img, image.Point{})
+		} else {
+			draw.Draw(out, img.Bounds(), img, image.Point{}, draw.Src)
+		}
+		return out
+	}
+
+	out := image.NewPaletted(img.Bounds(), colors)
+	index := make(map[color.RGBA]uint8, len(colors))
+	for i, c := range colors {
+		index[c.(color.RGBA)] = uint8(i)
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		c := color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 0xff}
+		out.Pix[i/4] = index[c]
+	}
+	return out
+}
+
+// exactPalette returns the distinct colors of img, or nil if there are more than limit
+func exactPalette(img *image.RGBA, limit int) color.Palette {
+	seen := make(map[color.RGBA]bool)
+	var colors color.Palette
+	for i := 0; i < len(img.Pix); i += 4 {
+		c := color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 0xff}
+		if seen[c] {
+			continue
+		}
+		if len(colors) == limit {
+			return nil
+		}
+		seen[c] = true
+		colors = append(colors, c)
+	}
+	return colors
+}
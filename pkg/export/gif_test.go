@@ -0,0 +1,107 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testFrame returns a frame filled with c, with one pixel of mark in the corner
+func testFrame(width, height int, c, mark color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, 0xff
+	}
+	img.SetRGBA(0, 0, mark)
+	return img
+}
+
+// gradientFrame returns a frame with more colors than a GIF palette holds
+func gradientFrame(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), uint8(x + y), 0xff})
+		}
+	}
+	return img
+}
+
+func TestGIFEncoder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	frames := []*image.RGBA{
+		testFrame(20, 15, red, white),
+		testFrame(20, 15, blue, red),
+		gradientFrame(20, 15),
+	}
+
+	encoder := newGIFEncoder(path, false)
+	for _, frame := range frames {
+		if err := encoder.WriteFrame(frame, 33*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	anim, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("the GIF does not decode: %v", err)
+	}
+	if len(anim.Image) != len(frames) {
+		t.Fatalf("got %d frames, want %d", len(anim.Image), len(frames))
+	}
+	if anim.LoopCount != 0 {
+		t.Errorf("loop count is %d, want 0 to loop forever", anim.LoopCount)
+	}
+	// 33 ms frames add up to 10 hundredths of a second every 3 frames
+	if want := []int{3, 4, 3}; anim.Delay[0] != want[0] || anim.Delay[1] != want[1] || anim.Delay[2] != want[2] {
+		t.Errorf("delays are %v, want %v", anim.Delay, want)
+	}
+	for i, want := range frames[:2] {
+		got := anim.Image[i]
+		for _, p := range []image.Point{{0, 0}, {3, 2}, {19, 14}} {
+			r, g, b, _ := got.At(p.X, p.Y).RGBA()
+			if c := want.RGBAAt(p.X, p.Y); uint8(r>>8) != c.R || uint8(g>>8) != c.G || uint8(b>>8) != c.B {
+				t.Errorf("frame %d pixel %v is %v, want %v", i, p, got.At(p.X, p.Y), c)
+			}
+		}
+	}
+}
+
+func TestGIFEncoderRejectsSizeChange(t *testing.T) {
+	encoder := newGIFEncoder(filepath.Join(t.TempDir(), "out.gif"), false)
+	defer encoder.Close()
+	black := color.RGBA{0, 0, 0, 0xff}
+	if err := encoder.WriteFrame(testFrame(4, 4, black, black), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.WriteFrame(testFrame(5, 4, black, black), time.Second); err == nil {
+		t.Error("a frame of another size was accepted")
+	}
+}
+
+func TestEncodersWithoutFrames(t *testing.T) {
+	dir := t.TempDir()
+	for _, encoder := range []Encoder{newGIFEncoder(filepath.Join(dir, "out.gif"), false), newAPNGEncoder(filepath.Join(dir, "out.png"))} {
+		if err := encoder.Close(); err == nil {
+			t.Errorf("%T closed without frames", encoder)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files were created without frames: %v", entries)
+	}
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// mp4Codec is the FourCC of the MPEG-4 Part 2 codec, which OpenCV builds can write without extra libraries
+const mp4Codec = "mp4v"
+
+// videoEncoder writes MP4 files with gocv's VideoWriter. The writer is opened
+// with the size of the first frame, and since MP4 has a fixed frame rate,
+// frames shown longer than one frame interval are repeated.
+type videoEncoder struct {
+	path   string
+	fps    float64
+	writer *gocv.VideoWriter
+}
+
+func newVideoEncoder(path string, fps float64) *videoEncoder {
+	return &videoEncoder{path: path, fps: fps}
+}
+
+// WriteFrame implements Encoder
+func (e *videoEncoder) WriteFrame(img *image.RGBA, delay time.Duration) error {
+	if e.writer == nil {
+		writer, err := gocv.VideoWriterFile(e.path, mp4Codec, e.fps, img.Bounds().Dx(), img.Bounds().Dy(), true)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %v", e.path, err)
+		}
+		if !writer.IsOpened() {
+			writer.Close()
+			return fmt.Errorf("failed to open %s: OpenCV has no %s encoder", e.path, mp4Codec)
+		}
+		e.writer = writer
+	}
+
+	mat, err := gocv.ImageToMatRGB(img)
+	if err != nil {
+		return fmt.Errorf("failed to convert frame: %v", err)
+	}
+	defer mat.Close()
+
+	repeats := int(math.Round(delay.Seconds() * e.fps))
+	if repeats < 1 {
+		repeats = 1
+	}
+	for i := 0; i < repeats; i++ {
+		if err := e.writer.Write(mat); err != nil {
+			return fmt.Errorf("failed to write frame: %v", err)
+		}
+	}
+	return nil
+}
+
+// Close implements Encoder
+func (e *videoEncoder) Close() error {
+	if e.writer == nil {
+		return fmt.Errorf("no frames to write to %s", e.path)
+	}
+	return e.writer.Close()
+}
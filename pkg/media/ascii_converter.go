@@ -1,15 +1,25 @@
 package media
 
 import (
-	"bytes"
 	"fmt"
 	"image"
 	"runtime"
 	"sync"
 
+	"github.com/kweonminsung/console-cinema/pkg/types"
 	"gocv.io/x/gocv"
 )
 
+func init() {
+	RegisterRenderer("ascii", func(config types.PlayerConfig) (Renderer, error) {
+		charset, err := ResolveCharset(config.Charset, config.InvertCharset, config.CalibrateCharset)
+		if err != nil {
+			return nil, err
+		}
+		return NewAsciiConverter(charset), nil
+	})
+}
+
 // DefaultCharset은 밝기 순서에 따라 정렬된 기본 ASCII 문자 집합입니다.
 const DefaultCharset = " .:-=+*#%@"
 
@@ -19,19 +29,23 @@ type AsciiConverter struct {
 }
 
 // NewAsciiConverter는 새로운 AsciiConverter 인스턴스를 생성합니다.
-func NewAsciiConverter() *AsciiConverter {
+// charset은 어두운 문자부터 밝은 문자 순서여야 하며, 비어 있으면 DefaultCharset을 사용합니다.
+func NewAsciiConverter(charset string) *AsciiConverter {
+	if charset == "" {
+		charset = DefaultCharset
+	}
 	return &AsciiConverter{
-		charset: []rune(DefaultCharset),
+		charset: []rune(charset),
 	}
 }
 
-// Convert는 gocv.Mat 이미지를 지정된 너비와 높이의 ASCII 문자열로 변환합니다.
-// color가 true이면 ANSI 컬러 코드 기반 ASCII 출력, false이면 흑백 ASCII 출력.
-func (c *AsciiConverter) Convert(img gocv.Mat, width, height int, color bool) (string, error) {
+// Convert는 gocv.Mat 이미지를 지정된 너비와 높이의 ASCII 프레임으로 변환합니다.
+// color가 true이면 각 문자에 원본 픽셀의 색상을 전경색으로 지정하고, false이면 흑백으로 출력합니다.
+func (c *AsciiConverter) Convert(img gocv.Mat, width, height int, color bool) (*Frame, error) {
 	originalWidth := float64(img.Cols())
 	originalHeight := float64(img.Rows())
 	if originalWidth == 0 || originalHeight == 0 {
-		return "", fmt.Errorf("invalid image dimensions: %dx%d", int(originalWidth), int(originalHeight))
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", int(originalWidth), int(originalHeight))
 	}
 
 	newWidth, newHeight := width, height
@@ -40,82 +54,50 @@ func (c *AsciiConverter) Convert(img gocv.Mat, width, height int, color bool) (s
 	defer resized.Close()
 	gocv.Resize(img, &resized, image.Point{X: newWidth, Y: newHeight}, 0, 0, gocv.InterpolationLinear)
 
-	lines := make([]string, newHeight)
-	var wg sync.WaitGroup
-	numWorkers := runtime.NumCPU()
+	gray := gocv.NewMat()
+	defer gray.Close()
+	gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
+	grayData := gray.ToBytes()
 
+	var colorData []byte
 	if color {
-		gray := gocv.NewMat()
-		defer gray.Close()
-		gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
+		colorData = resized.ToBytes()
+	}
 
-		rowJobs := make(chan int, newHeight)
-		for y := 0; y < newHeight; y++ {
-			rowJobs <- y
-		}
-		close(rowJobs)
-
-		wg.Add(newHeight)
-		for i := 0; i < numWorkers; i++ {
-			go func() {
-				for y := range rowJobs {
-					var line bytes.Buffer
-					for x := 0; x < newWidth; x++ {
-						pixel := gray.GetUCharAt(y, x)
-						idx := int(float64(pixel) / 255.0 * float64(len(c.charset)))
-						if idx >= len(c.charset) {
-							idx = len(c.charset) - 1
-						}
-						ch := c.charset[idx]
-
-						vec := resized.GetVecbAt(y, x)
-						b, g, r := vec[0], vec[1], vec[2]
-
-						line.WriteString(fmt.Sprintf("[#%02x%02x%02x]%c", r, g, b, ch))
+	frame := NewFrame(newWidth, newHeight)
+	var wg sync.WaitGroup
+	numWorkers := runtime.NumCPU()
+
+	rowJobs := make(chan int, newHeight)
+	for y := 0; y < newHeight; y++ {
+		rowJobs <- y
+	}
+	close(rowJobs)
+
+	wg.Add(newHeight)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			for y := range rowJobs {
+				row := frame.Row(y)
+				for x := 0; x < newWidth; x++ {
+					offset := y*newWidth + x
+					pixel := grayData[offset]
+					idx := int(float64(pixel) / 255.0 * float64(len(c.charset)))
+					if idx >= len(c.charset) {
+						idx = len(c.charset) - 1
 					}
-					lines[y] = line.String()
-					wg.Done()
-				}
-			}()
-		}
-		wg.Wait()
-	} else {
-		gray := gocv.NewMat()
-		defer gray.Close()
-		gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
-
-		rowJobs := make(chan int, newHeight)
-		for y := 0; y < newHeight; y++ {
-			rowJobs <- y
-		}
-		close(rowJobs)
-
-		wg.Add(newHeight)
-		for i := 0; i < numWorkers; i++ {
-			go func() {
-				for y := range rowJobs {
-					var line bytes.Buffer
-					for x := 0; x < newWidth; x++ {
-						pixel := gray.GetUCharAt(y, x)
-						idx := int(float64(pixel) / 255.0 * float64(len(c.charset)))
-						if idx >= len(c.charset) {
-							idx = len(c.charset) - 1
-						}
-						line.WriteRune(c.charset[idx])
+					row[x].Rune = c.charset[idx]
+
+					if color {
+						b, g, r := colorData[offset*3], colorData[offset*3+1], colorData[offset*3+2]
+						row[x].Fg = NewRGBColor(r, g, b)
 					}
-					lines[y] = line.String()
-					wg.Done()
 				}
-			}()
-		}
-		wg.Wait()
-	}
-
-	var buffer bytes.Buffer
-	for _, line := range lines {
-		buffer.WriteString(line)
-		buffer.WriteRune('\n')
+				wg.Done()
+			}
+		}()
 	}
+	wg.Wait()
 
-	return buffer.String(), nil
+	return frame, nil
 }
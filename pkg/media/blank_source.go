@@ -0,0 +1,100 @@
+package media
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// blankFrameSize는 BlankSource가 만드는 빈 이미지의 한 변 길이(픽셀)입니다.
+const blankFrameSize = 2
+
+// BlankSource는 영상 없이 길이와 FPS만 있는 프레임 소스입니다.
+// 오디오만 재생할 때 플레이어의 탐색, 타임라인, 구간 반복을 그대로 쓸 수 있도록
+// 지정된 길이만큼 내용이 없는 검은 프레임을 돌려줍니다.
+type BlankSource struct {
+	fps         float64
+	totalFrames int
+	// next는 다음에 읽을 프레임 번호이고, position은 마지막으로 읽거나 탐색한 위치입니다.
+	next     int
+	position time.Duration
+}
+
+var _ FrameSource = (*BlankSource)(nil)
+
+// NewBlankSource는 duration 길이의 BlankSource를 생성합니다. fps가 0 이하이면 30을 사용합니다.
+func NewBlankSource(duration time.Duration, fps float64) *BlankSource {
+	if fps <= 0 {
+		fps = 30
+	}
+	return &BlankSource{
+		fps:         fps,
+		totalFrames: int(math.Ceil(duration.Seconds() * fps)),
+	}
+}
+
+// ReadNextFrame은 다음 위치의 빈 프레임을 반환합니다.
+func (s *BlankSource) ReadNextFrame() (gocv.Mat, error) {
+	if s.next >= s.totalFrames {
+		return gocv.Mat{}, fmt.Errorf("failed to read frame or end of stream")
+	}
+	s.position = s.frameTime(s.next)
+	s.next++
+	return gocv.NewMatWithSize(blankFrameSize, blankFrameSize, gocv.MatTypeCV8UC3), nil
+}
+
+// GetFrameAt은 지정된 시간의 빈 프레임을 가져옵니다.
+func (s *BlankSource) GetFrameAt(d time.Duration) (gocv.Mat, error) {
+	if err := s.Seek(d); err != nil {
+		return gocv.Mat{}, err
+	}
+	return s.ReadNextFrame()
+}
+
+// Seek는 다음에 읽을 프레임을 d에 가장 가까운 프레임으로 옮깁니다.
+func (s *BlankSource) Seek(d time.Duration) error {
+	frame := int(math.Round(d.Seconds() * s.fps))
+	s.next = max(0, min(frame, s.totalFrames))
+	s.position = s.frameTime(s.next)
+	return nil
+}
+
+// frameTime은 프레임 번호의 표시 시각을 반환합니다.
+func (s *BlankSource) frameTime(frame int) time.Duration {
+	return time.Duration(float64(frame) / s.fps * float64(time.Second))
+}
+
+// GetFPS는 프레임 소스의 FPS를 반환합니다.
+func (s *BlankSource) GetFPS() float64 {
+	return s.fps
+}
+
+// GetWidth는 빈 프레임의 너비를 반환합니다.
+func (s *BlankSource) GetWidth() int {
+	return blankFrameSize
+}
+
+// GetHeight는 빈 프레임의 높이를 반환합니다.
+func (s *BlankSource) GetHeight() int {
+	return blankFrameSize
+}
+
+// GetPosition은 마지막으로 읽거나 탐색한 위치를 반환합니다.
+func (s *BlankSource) GetPosition() time.Duration {
+	return s.position
+}
+
+// GetCurrentFrame은 다음에 읽을 프레임 번호를 반환합니다.
+func (s *BlankSource) GetCurrentFrame() int {
+	return s.next
+}
+
+// GetTotalFrames는 전체 프레임 수를 반환합니다.
+func (s *BlankSource) GetTotalFrames() int {
+	return s.totalFrames
+}
+
+// Close는 해제할 리소스가 없으므로 아무 일도 하지 않습니다.
+func (s *BlankSource) Close() {}
@@ -0,0 +1,194 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+func init() {
+	RegisterRenderer("quadrant", func(config types.PlayerConfig) (Renderer, error) {
+		return NewQuadrantConverter(), nil
+	})
+	RegisterRenderer("sextant", func(config types.PlayerConfig) (Renderer, error) {
+		if config.SextantFallback {
+			return NewQuadrantConverter(), nil
+		}
+		return NewSextantConverter(), nil
+	})
+}
+
+// quadrantGlyphs는 2x2 사분면 패턴에 해당하는 블록 문자입니다.
+// 비트 순서는 왼쪽 위(1), 오른쪽 위(2), 왼쪽 아래(4), 오른쪽 아래(8)입니다.
+var quadrantGlyphs = []rune(" ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█")
+
+// sextantGlyphs는 2x3 육분면 패턴에 해당하는 문자입니다 (Symbols for Legacy Computing).
+// 비트 순서는 위에서 아래, 왼쪽에서 오른쪽으로 1, 2, 4, 8, 16, 32입니다.
+var sextantGlyphs = buildSextantGlyphs()
+
+// buildSextantGlyphs는 64개의 육분면 패턴 표를 생성합니다.
+// U+1FB00부터 시작하는 60개의 문자에는 빈 칸, 꽉 찬 칸, 왼쪽/오른쪽 절반이 빠져 있으므로
+// 해당 패턴은 기존 블록 문자로 채웁니다.
+func buildSextantGlyphs() []rune {
+	glyphs := make([]rune, 64)
+	next := rune(0x1FB00)
+	for pattern := 1; pattern < 63; pattern++ {
+		switch pattern {
+		case 21:
+			glyphs[pattern] = '▌'
+		case 42:
+			glyphs[pattern] = '▐'
+		default:
+			glyphs[pattern] = next
+			next++
+		}
+	}
+	glyphs[0] = ' '
+	glyphs[63] = '█'
+	return glyphs
+}
+
+// BlockConverter는 각 셀을 2xN 개의 하위 픽셀로 나누고, 이를 가장 잘 근사하는
+// 두 가지 색상(전경/배경)과 블록 문자를 선택합니다.
+type BlockConverter struct {
+	cellRows int
+	glyphs   []rune
+}
+
+// NewQuadrantConverter는 셀을 2x2 사분면으로 나누는 BlockConverter를 생성합니다.
+func NewQuadrantConverter() *BlockConverter {
+	return &BlockConverter{cellRows: 2, glyphs: quadrantGlyphs}
+}
+
+// NewSextantConverter는 셀을 2x3 육분면으로 나누는 BlockConverter를 생성합니다.
+// 터미널 폰트에 육분면 문자가 없다면 NewQuadrantConverter를 대신 사용하세요.
+func NewSextantConverter() *BlockConverter {
+	return &BlockConverter{cellRows: 3, glyphs: sextantGlyphs}
+}
+
+// Convert는 gocv.Mat을 셀마다 전경색과 배경색이 지정된 블록 문자 프레임으로 변환합니다.
+// color가 false이면 회색조로 출력합니다.
+func (c *BlockConverter) Convert(img gocv.Mat, width, height int, color bool) (*Frame, error) {
+	originalWidth := img.Cols()
+	originalHeight := img.Rows()
+	if originalWidth == 0 || originalHeight == 0 {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", originalWidth, originalHeight)
+	}
+
+	cols, rows := fitCells(originalWidth, originalHeight, width, height)
+	pixelWidth, pixelHeight := cols*2, rows*c.cellRows
+
+	resized := gocv.NewMat()
+	defer resized.Close()
+	gocv.Resize(img, &resized, image.Pt(pixelWidth, pixelHeight), 0, 0, gocv.InterpolationArea)
+
+	channels := 3
+	data := resized.ToBytes()
+	if !color {
+		gray := gocv.NewMat()
+		defer gray.Close()
+		gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
+		data = gray.ToBytes()
+		channels = 1
+	}
+
+	frame := NewFrame(cols, rows)
+	var wg sync.WaitGroup
+	numWorkers := runtime.NumCPU()
+
+	rowJobs := make(chan int, rows)
+	for y := 0; y < rows; y++ {
+		rowJobs <- y
+	}
+	close(rowJobs)
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			pixels := make([][3]int, 2*c.cellRows)
+			for y := range rowJobs {
+				row := frame.Row(y)
+				for x := 0; x < cols; x++ {
+					for dy := 0; dy < c.cellRows; dy++ {
+						for dx := 0; dx < 2; dx++ {
+							offset := ((y*c.cellRows+dy)*pixelWidth + x*2 + dx) * channels
+							if channels == 1 {
+								v := int(data[offset])
+								pixels[dy*2+dx] = [3]int{v, v, v}
+							} else {
+								pixels[dy*2+dx] = [3]int{int(data[offset+2]), int(data[offset+1]), int(data[offset])}
+							}
+						}
+					}
+
+					pattern, fg, bg := bestPartition(pixels)
+					row[x] = Cell{
+						Rune: c.glyphs[pattern],
+						Fg:   NewRGBColor(uint8(fg[0]), uint8(fg[1]), uint8(fg[2])),
+						Bg:   NewRGBColor(uint8(bg[0]), uint8(bg[1]), uint8(bg[2])),
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return frame, nil
+}
+
+// bestPartition은 하위 픽셀들을 두 그룹으로 나누는 모든 경우를 비교하여
+// 제곱 오차가 가장 작은 분할과 각 그룹의 평균 색상을 반환합니다.
+// 반환되는 pattern의 비트가 켜진 픽셀은 전경색(fg)으로 그려집니다.
+func bestPartition(pixels [][3]int) (int, [3]int, [3]int) {
+	n := len(pixels)
+	full := 1<<n - 1
+
+	var total [3]int
+	for _, p := range pixels {
+		total[0] += p[0]
+		total[1] += p[1]
+		total[2] += p[2]
+	}
+
+	// 그룹 내 제곱 오차의 합은 전체 제곱합에서 |합|²/개수를 뺀 값이므로,
+	// 두 그룹의 |합|²/개수 합이 가장 큰 분할이 최적입니다.
+	// 마지막 픽셀을 항상 배경 그룹에 두어 대칭인 분할을 한 번만 검사합니다.
+	bestMask := 0
+	bestScore := float64(total[0]*total[0]+total[1]*total[1]+total[2]*total[2]) / float64(n)
+	var bestFg, bestBg [3]int
+	for mask := 1; mask < 1<<(n-1); mask++ {
+		var sumA [3]int
+		countA := 0
+		for i, p := range pixels {
+			if mask&(1<<i) != 0 {
+				sumA[0] += p[0]
+				sumA[1] += p[1]
+				sumA[2] += p[2]
+				countA++
+			}
+		}
+		sumB := [3]int{total[0] - sumA[0], total[1] - sumA[1], total[2] - sumA[2]}
+		countB := n - countA
+
+		score := float64(sumA[0]*sumA[0]+sumA[1]*sumA[1]+sumA[2]*sumA[2])/float64(countA) +
+			float64(sumB[0]*sumB[0]+sumB[1]*sumB[1]+sumB[2]*sumB[2])/float64(countB)
+		if score > bestScore {
+			bestScore = score
+			bestMask = mask
+			bestFg = [3]int{sumA[0] / countA, sumA[1] / countA, sumA[2] / countA}
+			bestBg = [3]int{sumB[0] / countB, sumB[1] / countB, sumB[2] / countB}
+		}
+	}
+
+	if bestMask == 0 {
+		// 분할하지 않는 것이 가장 좋다면 꽉 찬 블록 하나로 표현합니다.
+		mean := [3]int{total[0] / n, total[1] / n, total[2] / n}
+		return full, mean, mean
+	}
+	return bestMask, bestFg, bestBg
+}
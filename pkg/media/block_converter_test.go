@@ -0,0 +1,72 @@
+package media
+
+import (
+	"testing"
+)
+
+func TestSextantGlyphs(t *testing.T) {
+	tests := []struct {
+		pattern int
+		want    rune
+	}{
+		{0, ' '},
+		{1, 0x1FB00},
+		{20, 0x1FB13},
+		{21, '▌'},
+		{22, 0x1FB14},
+		{41, 0x1FB27},
+		{42, '▐'},
+		{43, 0x1FB28},
+		{62, 0x1FB3B},
+		{63, '█'},
+	}
+	for _, test := range tests {
+		if got := sextantGlyphs[test.pattern]; got != test.want {
+			t.Errorf("pattern %d maps to %U, want %U", test.pattern, got, test.want)
+		}
+	}
+
+	if len(sextantGlyphs) != 64 {
+		t.Fatalf("got %d sextant glyphs, want 64", len(sextantGlyphs))
+	}
+	seen := make(map[rune]int)
+	for pattern, glyph := range sextantGlyphs {
+		if other, ok := seen[glyph]; ok {
+			t.Errorf("patterns %d and %d both map to %U", other, pattern, glyph)
+		}
+		seen[glyph] = pattern
+	}
+	if len(quadrantGlyphs) != 16 {
+		t.Errorf("got %d quadrant glyphs, want 16", len(quadrantGlyphs))
+	}
+}
+
+func TestBestPartition(t *testing.T) {
+	black := [3]int{0, 0, 0}
+	white := [3]int{255, 255, 255}
+	gray := [3]int{100, 100, 100}
+	tests := []struct {
+		name    string
+		pixels  [][3]int
+		pattern int
+		fg, bg  [3]int
+	}{
+		{"uniform", [][3]int{gray, gray, gray, gray}, 15, gray, gray},
+		{"top half", [][3]int{white, white, black, black}, 1 | 2, white, black},
+		{"last pixel bright", [][3]int{black, black, black, white}, 1 | 2 | 4, black, white},
+		{"left column", [][3]int{white, black, white, black, white, black}, 1 | 4 | 16, white, black},
+		{"single pixel", [][3]int{white, black, black, black, black, black}, 1, white, black},
+		{"nearly uniform", [][3]int{{10, 10, 10}, {10, 10, 10}, {12, 12, 12}, {12, 12, 12}}, 1 | 2, [3]int{10, 10, 10}, [3]int{12, 12, 12}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pattern, fg, bg := bestPartition(test.pixels)
+			if pattern != test.pattern || fg != test.fg || bg != test.bg {
+				t.Errorf("bestPartition() = %d, %v, %v, want %d, %v, %v", pattern, fg, bg, test.pattern, test.fg, test.bg)
+			}
+			if last := 1 << (len(test.pixels) - 1); pattern != 1<<len(test.pixels)-1 && pattern&last != 0 {
+				t.Errorf("the last pixel is in the foreground of pattern %d", pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+func init() {
+	RegisterRenderer("braille", func(config types.PlayerConfig) (Renderer, error) {
+		mode, err := ParseDitherMode(config.Dither)
+		if err != nil {
+			return nil, err
+		}
+		return NewBrailleConverter(mode), nil
+	})
+}
+
+// brailleBase는 점이 하나도 없는 점자 패턴(U+2800)입니다.
+const brailleBase = 0x2800
+
+// brailleDots는 셀 안의 (x, y) 위치에 해당하는 점자 비트입니다.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// BrailleConverter는 2x4 픽셀 블록을 하나의 점자 문자로 매핑하여
+// ASCII 변환보다 8배 높은 공간 해상도를 제공합니다.
+type BrailleConverter struct {
+	dither DitherMode
+}
+
+// NewBrailleConverter는 주어진 디더링 방식을 사용하는 BrailleConverter를 생성합니다.
+func NewBrailleConverter(dither DitherMode) *BrailleConverter {
+	return &BrailleConverter{dither: dither}
+}
+
+// Convert는 gocv.Mat을 점자 문자 프레임으로 변환합니다.
+// color가 true이면 각 셀의 전경색으로 블록 평균 색상을 사용합니다.
+func (c *BrailleConverter) Convert(img gocv.Mat, width, height int, color bool) (*Frame, error) {
+	originalWidth := img.Cols()
+	originalHeight := img.Rows()
+	if originalWidth == 0 || originalHeight == 0 {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", originalWidth, originalHeight)
+	}
+
+	cols, rows := fitCells(originalWidth, originalHeight, width, height)
+	dotWidth, dotHeight := cols*2, rows*4
+
+	resized := gocv.NewMat()
+	defer resized.Close()
+	gocv.Resize(img, &resized, image.Pt(dotWidth, dotHeight), 0, 0, gocv.InterpolationArea)
+
+	gray := gocv.NewMat()
+	defer gray.Close()
+	gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
+
+	// 오차 확산은 이미지 전체에 걸쳐 순차적으로 진행되므로 먼저 한 번에 이진화합니다.
+	dots := binarize(gray.ToBytes(), dotWidth, dotHeight, c.dither)
+
+	var colorData []byte
+	if color {
+		colorData = resized.ToBytes()
+	}
+
+	frame := NewFrame(cols, rows)
+	var wg sync.WaitGroup
+	numWorkers := runtime.NumCPU()
+
+	rowJobs := make(chan int, rows)
+	for y := 0; y < rows; y++ {
+		rowJobs <- y
+	}
+	close(rowJobs)
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for y := range rowJobs {
+				row := frame.Row(y)
+				for x := 0; x < cols; x++ {
+					pattern := rune(0)
+					var sumR, sumG, sumB int
+					for dy := 0; dy < 4; dy++ {
+						py := y*4 + dy
+						for dx := 0; dx < 2; dx++ {
+							px := x*2 + dx
+							if dots[py*dotWidth+px] {
+								pattern |= brailleDots[dy][dx]
+							}
+							if color {
+								offset := (py*dotWidth + px) * 3
+								sumB += int(colorData[offset])
+								sumG += int(colorData[offset+1])
+								sumR += int(colorData[offset+2])
+							}
+						}
+					}
+
+					if pattern == 0 {
+						continue
+					}
+
+					row[x].Rune = brailleBase + pattern
+					if color {
+						row[x].Fg = NewRGBColor(uint8(sumR/8), uint8(sumG/8), uint8(sumB/8))
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return frame, nil
+}
@@ -0,0 +1,92 @@
+package media
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExtendedCharset은 70단계의 밝기를 표현하는 확장 ASCII 문자 집합입니다.
+const ExtendedCharset = " .'`^\",:;Il!i><~+_-?][}{1)(|\\/tfjrxnuvczXYUJCLQ0OZmwqpdbkhao*#MW&8%B@$"
+
+// BlockCharset은 음영 블록 문자로 이루어진 문자 집합입니다.
+const BlockCharset = " ░▒▓█"
+
+// DigitCharset은 내장 폰트에서 측정한 잉크 양 순서로 정렬된 숫자 문자 집합입니다.
+const DigitCharset = " 7104236958"
+
+// CharsetPresets는 --charset에서 이름으로 선택할 수 있는 문자 집합입니다.
+var CharsetPresets = map[string]string{
+	"standard": DefaultCharset,
+	"extended": ExtendedCharset,
+	"blocks":   BlockCharset,
+	"digits":   DigitCharset,
+}
+
+// CharsetPresetNames는 프리셋 이름을 정렬하여 반환합니다.
+func CharsetPresetNames() []string {
+	names := make([]string, 0, len(CharsetPresets))
+	for name := range CharsetPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveCharset은 프리셋 이름 또는 사용자 지정 문자열로부터 어두운 순서의 문자 집합을 만듭니다.
+// calibrate가 true이면 내장 폰트로 측정한 잉크 양에 따라 문자를 다시 정렬하고,
+// invert가 true이면 밝은 배경 터미널을 위해 순서를 뒤집습니다.
+func ResolveCharset(name string, invert, calibrate bool) (string, error) {
+	charset, ok := CharsetPresets[name]
+	if !ok {
+		charset = name
+	}
+	if charset == "" {
+		charset = DefaultCharset
+	}
+
+	if calibrate {
+		calibrated, err := CalibrateCharset(charset)
+		if err != nil {
+			return "", err
+		}
+		charset = calibrated
+	}
+
+	if invert {
+		runes := []rune(charset)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		charset = string(runes)
+	}
+
+	return charset, nil
+}
+
+// CalibrateCharset은 문자열의 각 문자를 내장 폰트로 래스터화하여 잉크 양이 적은 순서로 정렬합니다.
+// 중복된 문자는 제거되며, 잉크 양이 같은 문자는 입력 순서를 유지합니다.
+func CalibrateCharset(charset string) (string, error) {
+	var runes []rune
+	coverage := make(map[rune]float64)
+	for _, r := range charset {
+		if _, seen := coverage[r]; seen {
+			continue
+		}
+		if !hasGlyph(r) {
+			return "", fmt.Errorf("cannot calibrate %q: the bundled font only covers printable ASCII", r)
+		}
+		coverage[r] = glyphCoverage(r)
+		runes = append(runes, r)
+	}
+
+	sort.SliceStable(runes, func(i, j int) bool {
+		return coverage[runes[i]] < coverage[runes[j]]
+	})
+
+	var builder strings.Builder
+	for _, r := range runes {
+		builder.WriteRune(r)
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,84 @@
+package media
+
+import "fmt"
+
+// DitherMode는 회색조 이미지를 흑백 점으로 이진화하는 방식입니다.
+type DitherMode string
+
+const (
+	// DitherFloydSteinberg는 오차 확산 방식으로 부드러운 계조를 표현합니다.
+	DitherFloydSteinberg DitherMode = "fs"
+	// DitherOrdered는 4x4 Bayer 행렬을 사용하는 정렬 디더링입니다.
+	DitherOrdered DitherMode = "ordered"
+	// DitherThreshold는 고정 임계값으로 단순 이진화합니다.
+	DitherThreshold DitherMode = "threshold"
+)
+
+// DitherModes는 지원하는 모든 디더링 방식입니다.
+var DitherModes = []DitherMode{DitherFloydSteinberg, DitherOrdered, DitherThreshold}
+
+// bayer4x4는 정렬 디더링에 사용되는 4x4 Bayer 행렬입니다.
+var bayer4x4 = [4][4]int{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// ParseDitherMode는 문자열을 DitherMode로 변환합니다. 빈 문자열은 Floyd–Steinberg로 처리합니다.
+func ParseDitherMode(s string) (DitherMode, error) {
+	if s == "" {
+		return DitherFloydSteinberg, nil
+	}
+	for _, mode := range DitherModes {
+		if DitherMode(s) == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown dither mode %q (available: %v)", s, DitherModes)
+}
+
+// binarize는 width x height 크기의 회색조 데이터를 켜진 점(true)과 꺼진 점으로 변환합니다.
+func binarize(gray []byte, width, height int, mode DitherMode) []bool {
+	dots := make([]bool, width*height)
+
+	switch mode {
+	case DitherOrdered:
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				threshold := (bayer4x4[y%4][x%4]*16 + 8)
+				dots[y*width+x] = int(gray[y*width+x]) > threshold
+			}
+		}
+	case DitherThreshold:
+		for i, v := range gray {
+			dots[i] = v >= 128
+		}
+	default:
+		// 오차를 오른쪽과 아래 행으로 확산시키기 위해 두 행 분량의 버퍼만 유지합니다.
+		cur := make([]float64, width+2)
+		next := make([]float64, width+2)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				old := float64(gray[y*width+x]) + cur[x+1]
+				on := old >= 128
+				dots[y*width+x] = on
+
+				quantErr := old
+				if on {
+					quantErr = old - 255
+				}
+				cur[x+2] += quantErr * 7 / 16
+				next[x] += quantErr * 3 / 16
+				next[x+1] += quantErr * 5 / 16
+				next[x+2] += quantErr * 1 / 16
+			}
+			cur, next = next, cur
+			for i := range next {
+				next[i] = 0
+			}
+		}
+	}
+
+	return dots
+}
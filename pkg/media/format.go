@@ -0,0 +1,96 @@
+package media
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FrameFormat은 프레임을 텍스트로 내보내는 형식입니다.
+type FrameFormat string
+
+const (
+	// FormatPlain은 색상 없이 문자만 기록합니다.
+	FormatPlain FrameFormat = "plain"
+	// FormatANSI는 SGR 이스케이프 시퀀스로 색상과 속성을 기록합니다.
+	FormatANSI FrameFormat = "ansi"
+	// FormatMarkup은 ParseMarkup으로 다시 읽을 수 있는 "[#rrggbb]" 태그 형식입니다.
+	FormatMarkup FrameFormat = "markup"
+)
+
+// FrameFormats는 지원하는 모든 출력 형식입니다.
+var FrameFormats = []FrameFormat{FormatPlain, FormatANSI, FormatMarkup}
+
+// ParseFrameFormat은 문자열을 FrameFormat으로 변환합니다. 빈 문자열은 ANSI 형식으로 처리합니다.
+func ParseFrameFormat(s string) (FrameFormat, error) {
+	if s == "" {
+		return FormatANSI, nil
+	}
+	for _, format := range FrameFormats {
+		if FrameFormat(s) == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown frame format %q (available: %v)", s, FrameFormats)
+}
+
+// Encode는 프레임을 주어진 형식의 문자열로 직렬화합니다.
+func (f *Frame) Encode(format FrameFormat) string {
+	switch format {
+	case FormatPlain:
+		return f.PlainText()
+	case FormatMarkup:
+		return f.Markup()
+	default:
+		return f.ANSI()
+	}
+}
+
+// ANSI는 프레임을 SGR 이스케이프 시퀀스로 색칠된 문자열로 직렬화합니다.
+// 스타일이 바뀌는 셀에서만 시퀀스를 기록하며, 스타일이 남아 있는 줄은 끝에서 초기화합니다.
+func (f *Frame) ANSI() string {
+	var builder strings.Builder
+	for y := 0; y < f.Height; y++ {
+		var style Cell
+		for _, cell := range f.Row(y) {
+			if cell.Fg != style.Fg || cell.Bg != style.Bg || cell.Attrs != style.Attrs {
+				builder.WriteString(ansiStyle(cell))
+				style = cell
+			}
+			builder.WriteRune(cell.Rune)
+		}
+		if style.Fg != ColorDefault || style.Bg != ColorDefault || style.Attrs != 0 {
+			builder.WriteString("\x1b[0m")
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// ansiStyle은 셀의 색상과 속성을 처음부터 지정하는 SGR 시퀀스를 반환합니다.
+func ansiStyle(cell Cell) string {
+	params := []string{"0"}
+	if cell.Attrs&AttrBold != 0 {
+		params = append(params, "1")
+	}
+	if cell.Attrs&AttrReverse != 0 {
+		params = append(params, "7")
+	}
+	params = append(params, ansiColorParams(cell.Fg, 38)...)
+	params = append(params, ansiColorParams(cell.Bg, 48)...)
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// ansiColorParams는 색상을 SGR 매개변수로 변환합니다. base는 전경색이면 38, 배경색이면 48입니다.
+// 기본 색상은 "0"으로 이미 초기화되므로 매개변수가 없습니다.
+func ansiColorParams(c Color, base int) []string {
+	switch {
+	case c.IsRGB():
+		r, g, b := c.RGB()
+		return []string{strconv.Itoa(base), "2", strconv.Itoa(int(r)), strconv.Itoa(int(g)), strconv.Itoa(int(b))}
+	case c.IsIndex():
+		return []string{strconv.Itoa(base), "5", strconv.Itoa(c.Index())}
+	default:
+		return nil
+	}
+}
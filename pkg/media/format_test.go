@@ -0,0 +1,94 @@
+package media
+
+import (
+	"testing"
+)
+
+func TestFrameANSI(t *testing.T) {
+	red := NewRGBColor(0xff, 0, 0)
+	blue := NewRGBColor(0, 0, 0xff)
+	tests := []struct {
+		name  string
+		cells [][]Cell
+		want  string
+	}{
+		{
+			name:  "default style has no sequences",
+			cells: [][]Cell{{{Rune: 'a'}, {Rune: 'b'}}, {{Rune: 'c'}, {Rune: 'd'}}},
+			want:  "ab\ncd\n",
+		},
+		{
+			name:  "one sequence for a run of the same style",
+			cells: [][]Cell{{{Rune: 'a', Fg: red}, {Rune: 'b', Fg: red}, {Rune: 'c', Fg: red}}},
+			want:  "\x1b[0;38;2;255;0;0mabc\x1b[0m\n",
+		},
+		{
+			name:  "a new sequence on every change",
+			cells: [][]Cell{{{Rune: 'a', Fg: red}, {Rune: 'b', Fg: red, Bg: blue}, {Rune: 'c', Fg: red}}},
+			want:  "\x1b[0;38;2;255;0;0ma\x1b[0;38;2;255;0;0;48;2;0;0;255mb\x1b[0;38;2;255;0;0mc\x1b[0m\n",
+		},
+		{
+			name:  "no reset when the line returns to the default style",
+			cells: [][]Cell{{{Rune: 'a', Fg: red}, {Rune: 'b'}}},
+			want:  "\x1b[0;38;2;255;0;0ma\x1b[0mb\n",
+		},
+		{
+			name:  "every line starts from the default style",
+			cells: [][]Cell{{{Rune: 'a', Bg: blue}}, {{Rune: 'b', Bg: blue}}},
+			want:  "\x1b[0;48;2;0;0;255ma\x1b[0m\n\x1b[0;48;2;0;0;255mb\x1b[0m\n",
+		},
+		{
+			name:  "palette colors",
+			cells: [][]Cell{{{Rune: 'a', Fg: NewIndexColor(196), Bg: NewIndexColor(0)}}},
+			want:  "\x1b[0;38;5;196;48;5;0ma\x1b[0m\n",
+		},
+		{
+			name:  "attributes",
+			cells: [][]Cell{{{Rune: 'a', Attrs: AttrBold}, {Rune: 'b', Attrs: AttrBold | AttrReverse, Fg: red}, {Rune: 'c'}}},
+			want:  "\x1b[0;1ma\x1b[0;1;7;38;2;255;0;0mb\x1b[0mc\n",
+		},
+		{
+			name:  "attributes alone need a reset",
+			cells: [][]Cell{{{Rune: 'a', Attrs: AttrReverse}}},
+			want:  "\x1b[0;7ma\x1b[0m\n",
+		},
+		{
+			name:  "wide runes",
+			cells: [][]Cell{{{Rune: '가', Fg: blue}, {Rune: '▀', Fg: blue}}},
+			want:  "\x1b[0;38;2;0;0;255m가▀\x1b[0m\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			frame := NewFrame(len(test.cells[0]), len(test.cells))
+			for y, row := range test.cells {
+				for x, cell := range row {
+					frame.Set(x, y, cell)
+				}
+			}
+			if got := frame.ANSI(); got != test.want {
+				t.Errorf("ANSI() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseFrameFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    FrameFormat
+		wantErr bool
+	}{
+		{"", FormatANSI, false},
+		{"plain", FormatPlain, false},
+		{"ansi", FormatANSI, false},
+		{"markup", FormatMarkup, false},
+		{"html", "", true},
+	}
+	for _, test := range tests {
+		got, err := ParseFrameFormat(test.input)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("ParseFrameFormat(%q) = %q, %v, want %q (error: %v)", test.input, got, err, test.want, test.wantErr)
+		}
+	}
+}
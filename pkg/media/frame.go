@@ -0,0 +1,295 @@
+package media
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color는 셀의 색상입니다. 24비트 RGB 색상이거나 터미널 팔레트 인덱스이며,
+// 0(ColorDefault)은 터미널의 기본 색상을 의미합니다.
+type Color uint32
+
+const (
+	// ColorDefault는 터미널 기본 색상입니다.
+	ColorDefault Color = 0
+
+	colorIsRGB   Color = 1 << 24
+	colorIsIndex Color = 1 << 25
+)
+
+// NewRGBColor는 24비트 RGB 색상을 생성합니다.
+func NewRGBColor(r, g, b uint8) Color {
+	return colorIsRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// NewIndexColor는 터미널 팔레트 인덱스 색상을 생성합니다.
+func NewIndexColor(index int) Color {
+	return colorIsIndex | Color(index&0xff)
+}
+
+// IsRGB는 24비트 RGB 색상인지 확인합니다.
+func (c Color) IsRGB() bool {
+	return c&colorIsRGB != 0
+}
+
+// IsIndex는 팔레트 인덱스 색상인지 확인합니다.
+func (c Color) IsIndex() bool {
+	return c&colorIsIndex != 0
+}
+
+// RGB는 RGB 색상의 각 채널 값을 반환합니다.
+func (c Color) RGB() (uint8, uint8, uint8) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
+
+// Index는 팔레트 인덱스 색상의 인덱스를 반환합니다.
+func (c Color) Index() int {
+	return int(c & 0xff)
+}
+
+// Hex는 RGB 색상을 "#rrggbb" 형식으로 반환합니다.
+func (c Color) Hex() string {
+	r, g, b := c.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// AttrMask는 셀의 텍스트 속성입니다.
+type AttrMask uint8
+
+const (
+	// AttrReverse는 전경색과 배경색을 뒤바꿔 그립니다.
+	AttrReverse AttrMask = 1 << iota
+	// AttrBold는 굵은 글씨로 그립니다.
+	AttrBold
+)
+
+// Cell은 프레임의 한 칸입니다.
+type Cell struct {
+	Rune  rune
+	Fg    Color
+	Bg    Color
+	Attrs AttrMask
+}
+
+// Similar는 두 셀의 문자와 속성이 같고 색상의 채널별 차이가 모두 tolerance 이하인지 확인합니다.
+// 팔레트 인덱스 색상과 기본 색상은 정확히 같아야 합니다.
+func (c Cell) Similar(other Cell, tolerance int) bool {
+	if c.Rune != other.Rune || c.Attrs != other.Attrs {
+		return false
+	}
+	return similarColor(c.Fg, other.Fg, tolerance) && similarColor(c.Bg, other.Bg, tolerance)
+}
+
+// similarColor는 두 RGB 색상의 채널별 차이가 tolerance 이하인지 확인합니다.
+func similarColor(a, b Color, tolerance int) bool {
+	if a == b {
+		return true
+	}
+	if !a.IsRGB() || !b.IsRGB() || tolerance <= 0 {
+		return false
+	}
+
+	ar, ag, ab := a.RGB()
+	br, bg, bb := b.RGB()
+	for _, diff := range [3]int{int(ar) - int(br), int(ag) - int(bg), int(ab) - int(bb)} {
+		if diff > tolerance || -diff > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Frame은 컨버터가 생성하는 셀 격자입니다.
+type Frame struct {
+	Width  int
+	Height int
+	Cells  []Cell
+}
+
+// NewFrame은 기본 색상의 공백으로 채워진 width x height 프레임을 생성합니다.
+func NewFrame(width, height int) *Frame {
+	cells := make([]Cell, width*height)
+	for i := range cells {
+		cells[i].Rune = ' '
+	}
+	return &Frame{Width: width, Height: height, Cells: cells}
+}
+
+// Row는 y번째 행의 셀들을 반환합니다. 반환된 슬라이스를 수정하면 프레임도 바뀝니다.
+func (f *Frame) Row(y int) []Cell {
+	return f.Cells[y*f.Width : (y+1)*f.Width]
+}
+
+// At은 (x, y) 위치의 셀을 반환합니다.
+func (f *Frame) At(x, y int) Cell {
+	return f.Cells[y*f.Width+x]
+}
+
+// Set은 (x, y) 위치의 셀을 바꿉니다.
+func (f *Frame) Set(x, y int, cell Cell) {
+	f.Cells[y*f.Width+x] = cell
+}
+
+// Clone은 프레임의 복사본을 반환합니다.
+func (f *Frame) Clone() *Frame {
+	cells := make([]Cell, len(f.Cells))
+	copy(cells, f.Cells)
+	return &Frame{Width: f.Width, Height: f.Height, Cells: cells}
+}
+
+// PlainText는 색상 없이 문자만으로 이루어진 문자열을 반환합니다.
+func (f *Frame) PlainText() string {
+	var builder strings.Builder
+	for y := 0; y < f.Height; y++ {
+		for _, cell := range f.Row(y) {
+			builder.WriteRune(cell.Rune)
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// Markup은 프레임을 "[#rrggbb]" 색상 태그가 포함된 문자열로 직렬화합니다.
+// "[#rrggbb]"는 전경색만, "[#rrggbb:#rrggbb]"는 전경색과 배경색을 바꾸며, "-"는 기본 색상을 뜻합니다.
+// 문자 '['는 태그와 구분되도록 "[[]"로 기록됩니다.
+// 팔레트 인덱스 색상은 RGB 값을 알 수 없으므로 기본 색상으로 기록됩니다.
+func (f *Frame) Markup() string {
+	var builder strings.Builder
+	for y := 0; y < f.Height; y++ {
+		var fg, bg Color
+		for _, cell := range f.Row(y) {
+			cellFg, cellBg := markupColor(cell.Fg), markupColor(cell.Bg)
+			if cellBg != bg {
+				builder.WriteString("[" + markupColorSpec(cellFg) + ":" + markupColorSpec(cellBg) + "]")
+			} else if cellFg != fg {
+				builder.WriteString("[" + markupColorSpec(cellFg) + "]")
+			}
+			fg, bg = cellFg, cellBg
+
+			if cell.Rune == '[' {
+				builder.WriteString("[[]")
+				continue
+			}
+			builder.WriteRune(cell.Rune)
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// markupColor는 마크업으로 표현할 수 있는 RGB 색상만 남깁니다.
+func markupColor(c Color) Color {
+	if c.IsRGB() {
+		return c
+	}
+	return ColorDefault
+}
+
+// markupColorSpec은 마크업 태그 안에서 사용하는 색상 표기를 반환합니다.
+func markupColorSpec(c Color) string {
+	if c == ColorDefault {
+		return "-"
+	}
+	return c.Hex()
+}
+
+// ParseMarkup은 Markup 형식의 문자열을 프레임으로 변환합니다.
+// 가장 긴 줄의 길이가 프레임의 너비가 되며, 짧은 줄의 나머지는 공백으로 채워집니다.
+func ParseMarkup(markup string) *Frame {
+	lines := strings.Split(strings.TrimSuffix(markup, "\n"), "\n")
+	rows := make([][]Cell, len(lines))
+	width := 0
+	for y, line := range lines {
+		rows[y] = parseMarkupLine(line)
+		if len(rows[y]) > width {
+			width = len(rows[y])
+		}
+	}
+
+	frame := NewFrame(width, len(rows))
+	for y, row := range rows {
+		copy(frame.Row(y), row)
+	}
+	return frame
+}
+
+// maxMarkupTagLength는 가장 긴 색상 태그 "[#rrggbb:#rrggbb]"의 길이입니다.
+const maxMarkupTagLength = 17
+
+// parseMarkupLine은 마크업 한 줄을 셀들로 변환합니다.
+// 올바른 태그가 아닌 '['는 일반 문자로 처리합니다.
+func parseMarkupLine(line string) []Cell {
+	var cells []Cell
+	current := Cell{}
+	runes := []rune(line)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		if r == '[' {
+			if i+2 < len(runes) && runes[i+1] == '[' && runes[i+2] == ']' {
+				current.Rune = '['
+				cells = append(cells, current)
+				i += 3
+				continue
+			}
+			if length, ok := parseMarkupTag(runes[i:], &current); ok {
+				i += length
+				continue
+			}
+		}
+		current.Rune = r
+		cells = append(cells, current)
+		i++
+	}
+	return cells
+}
+
+// parseMarkupTag는 runes의 시작 부분에 있는 색상 태그를 해석하여 cell의 색상을 바꿉니다.
+// 태그의 길이와 성공 여부를 반환합니다.
+func parseMarkupTag(runes []rune, cell *Cell) (int, bool) {
+	end := -1
+	for j := 1; j < len(runes) && j < maxMarkupTagLength; j++ {
+		if runes[j] == ']' {
+			end = j
+			break
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+
+	specs := strings.Split(string(runes[1:end]), ":")
+	if len(specs) > 2 {
+		return 0, false
+	}
+	colors := make([]Color, len(specs))
+	for k, spec := range specs {
+		color, ok := parseColorSpec(spec)
+		if !ok {
+			return 0, false
+		}
+		colors[k] = color
+	}
+
+	cell.Fg = colors[0]
+	if len(colors) == 2 {
+		cell.Bg = colors[1]
+	}
+	return end + 1, true
+}
+
+// parseColorSpec은 "-" 또는 "#rrggbb" 형식의 색상 표기를 해석합니다.
+func parseColorSpec(spec string) (Color, bool) {
+	if spec == "-" {
+		return ColorDefault, true
+	}
+	if len(spec) != 7 || spec[0] != '#' {
+		return ColorDefault, false
+	}
+	rgb, err := strconv.ParseUint(spec[1:], 16, 32)
+	if err != nil {
+		return ColorDefault, false
+	}
+	return NewRGBColor(uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)), true
+}
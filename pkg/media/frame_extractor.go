@@ -19,6 +19,10 @@ type FrameExtractor struct {
 	fps       float64
 	width     int
 	height    int
+	// totalFrames는 열 때 한 번만 읽어 둡니다. VideoCapture는 스레드 안전하지 않으므로
+	// 디코딩 중에 UI 고루틴이 길이를 물어도 vc를 건드리지 않도록 합니다.
+	totalFrames int
+	seekMode    SeekMode
 }
 
 // NewFrameExtractor는 새로운 FrameExtractor 인스턴스를 생성합니다.
@@ -58,14 +62,17 @@ func NewFrameExtractor(source string, isYouTube bool) (*FrameExtractor, error) {
 	fps := vc.Get(gocv.VideoCaptureFPS)
 	width := int(vc.Get(gocv.VideoCaptureFrameWidth))
 	height := int(vc.Get(gocv.VideoCaptureFrameHeight))
+	totalFrames := int(vc.Get(gocv.VideoCaptureFrameCount))
 
 	return &FrameExtractor{
-		vc:        vc,
-		source:    source,
-		isYouTube: isYouTube,
-		fps:       fps,
-		width:     width,
-		height:    height,
+		vc:          vc,
+		source:      source,
+		isYouTube:   isYouTube,
+		fps:         fps,
+		width:       width,
+		height:      height,
+		totalFrames: totalFrames,
+		seekMode:    SeekAccurate,
 	}, nil
 }
 
@@ -84,19 +91,23 @@ func (c *FrameExtractor) ReadNextFrame() (gocv.Mat, error) {
 }
 
 // Seek는 비디오의 재생 위치를 지정된 시간으로 이동시킵니다.
+// 정확한 탐색 모드에서는 다음에 읽는 프레임이 지정된 시간의 프레임이 됩니다.
 func (c *FrameExtractor) Seek(d time.Duration) error {
-	ms := float64(d.Milliseconds())
-	c.vc.Set(gocv.VideoCapturePosMsec, ms)
-	return nil
+	if c.seekMode == SeekFast {
+		c.seekFast(d)
+		return nil
+	}
+	return c.seekAccurate(d)
 }
 
-// GetFrameAt은 지정된 시간의 프레임을 정확히 가져옵니다.
+// GetFrameAt은 지정된 시간의 프레임을 가져옵니다.
 // 내부적으로 Seek 후 ReadNextFrame을 호출합니다.
 func (c *FrameExtractor) GetFrameAt(d time.Duration) (gocv.Mat, error) {
-	if err := c.Seek(d); err != nil {
-		return gocv.Mat{}, err
+	// 정확한 탐색이 실패해도 빠른 탐색으로 대체되었으므로 가까운 프레임을 읽을 수 있습니다.
+	_ = c.Seek(d)
+	if c.seekMode == SeekFast {
+		c.vc.Grab(1)
 	}
-	c.vc.Grab(1)
 	return c.ReadNextFrame()
 }
 
@@ -128,7 +139,7 @@ func (c *FrameExtractor) GetCurrentFrame() int {
 
 // GetTotalFrames returns the total number of frames in the video.
 func (c *FrameExtractor) GetTotalFrames() int {
-	return int(c.vc.Get(gocv.VideoCaptureFrameCount))
+	return c.totalFrames
 }
 
 // Close는 사용된 모든 리소스를 해제합니다.
@@ -0,0 +1,144 @@
+package media
+
+import (
+	"testing"
+)
+
+// frameFromRows는 문자열 행과 셀별 색상으로 프레임을 만듭니다.
+func frameFromRows(rows []string, style func(x, y int) (Color, Color)) *Frame {
+	frame := NewFrame(len([]rune(rows[0])), len(rows))
+	for y, row := range rows {
+		for x, r := range []rune(row) {
+			fg, bg := style(x, y)
+			frame.Set(x, y, Cell{Rune: r, Fg: fg, Bg: bg})
+		}
+	}
+	return frame
+}
+
+func assertFramesEqual(t *testing.T, got, want *Frame) {
+	t.Helper()
+	if got.Width != want.Width || got.Height != want.Height {
+		t.Fatalf("got a %dx%d frame, want %dx%d", got.Width, got.Height, want.Width, want.Height)
+	}
+	for y := 0; y < want.Height; y++ {
+		for x := 0; x < want.Width; x++ {
+			if got.At(x, y) != want.At(x, y) {
+				t.Errorf("cell (%d, %d) is %+v, want %+v", x, y, got.At(x, y), want.At(x, y))
+			}
+		}
+	}
+}
+
+func TestMarkupRoundTrip(t *testing.T) {
+	red := NewRGBColor(0xff, 0, 0)
+	blue := NewRGBColor(0, 0, 0xff)
+	tests := []struct {
+		name  string
+		rows  []string
+		style func(x, y int) (Color, Color)
+	}{
+		{
+			name:  "plain",
+			rows:  []string{"abc", "def"},
+			style: func(x, y int) (Color, Color) { return ColorDefault, ColorDefault },
+		},
+		{
+			name: "escaped brackets",
+			rows: []string{"[[]x", "a[#]"},
+			style: func(x, y int) (Color, Color) {
+				if x == 1 {
+					return red, ColorDefault
+				}
+				return ColorDefault, ColorDefault
+			},
+		},
+		{
+			name: "background only changes",
+			rows: []string{"abcd"},
+			style: func(x, y int) (Color, Color) {
+				if x%2 == 1 {
+					return red, blue
+				}
+				return red, ColorDefault
+			},
+		},
+		{
+			name: "back to the default colors",
+			rows: []string{"abc", "abc"},
+			style: func(x, y int) (Color, Color) {
+				if x == 1 {
+					return red, blue
+				}
+				return ColorDefault, ColorDefault
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			frame := frameFromRows(test.rows, test.style)
+			assertFramesEqual(t, ParseMarkup(frame.Markup()), frame)
+		})
+	}
+}
+
+func TestMarkupTags(t *testing.T) {
+	red := NewRGBColor(0xff, 0, 0)
+	blue := NewRGBColor(0, 0, 0xff)
+	frame := NewFrame(4, 1)
+	frame.Set(0, 0, Cell{Rune: '['})
+	frame.Set(1, 0, Cell{Rune: 'a', Fg: red})
+	frame.Set(2, 0, Cell{Rune: 'b', Fg: red, Bg: blue})
+	frame.Set(3, 0, Cell{Rune: 'c'})
+
+	want := "[[][#ff0000]a[#ff0000:#0000ff]b[-:-]c\n"
+	if got := frame.Markup(); got != want {
+		t.Errorf("Markup() = %q, want %q", got, want)
+	}
+}
+
+func TestParseMarkupInvalidTag(t *testing.T) {
+	frame := ParseMarkup("[#zzzzzz]a[x")
+	if got := frame.PlainText(); got != "[#zzzzzz]a[x\n" {
+		t.Errorf("invalid tags were not kept as text: %q", got)
+	}
+}
+
+func TestParseMarkupIndexColor(t *testing.T) {
+	frame := NewFrame(1, 1)
+	frame.Set(0, 0, Cell{Rune: 'a', Fg: NewIndexColor(3)})
+	parsed := ParseMarkup(frame.Markup())
+	if fg := parsed.At(0, 0).Fg; fg != ColorDefault {
+		t.Errorf("palette color came back as %v, want the default color", fg)
+	}
+}
+
+func TestCellSimilar(t *testing.T) {
+	base := Cell{Rune: 'a', Fg: NewRGBColor(100, 100, 100), Bg: NewRGBColor(10, 10, 10)}
+	tests := []struct {
+		name      string
+		other     Cell
+		tolerance int
+		want      bool
+	}{
+		{"identical", base, 0, true},
+		{"at the tolerance", Cell{Rune: 'a', Fg: NewRGBColor(105, 95, 100), Bg: NewRGBColor(15, 10, 5)}, 5, true},
+		{"past the tolerance", Cell{Rune: 'a', Fg: NewRGBColor(106, 100, 100), Bg: base.Bg}, 5, false},
+		{"background past the tolerance", Cell{Rune: 'a', Fg: base.Fg, Bg: NewRGBColor(10, 16, 10)}, 5, false},
+		{"zero tolerance", Cell{Rune: 'a', Fg: NewRGBColor(101, 100, 100), Bg: base.Bg}, 0, false},
+		{"different rune", Cell{Rune: 'b', Fg: base.Fg, Bg: base.Bg}, 255, false},
+		{"different attributes", Cell{Rune: 'a', Fg: base.Fg, Bg: base.Bg, Attrs: AttrBold}, 255, false},
+		{"default against RGB", Cell{Rune: 'a', Bg: base.Bg}, 255, false},
+		{"palette index against RGB", Cell{Rune: 'a', Fg: NewIndexColor(7), Bg: base.Bg}, 255, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := base.Similar(test.other, test.tolerance); got != test.want {
+				t.Errorf("Similar(%+v, %d) = %t, want %t", test.other, test.tolerance, got, test.want)
+			}
+			if got := test.other.Similar(base, test.tolerance); got != test.want {
+				t.Errorf("Similar is not symmetric for %+v", test.other)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package media
+
+import (
+	"image"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// glyphFace는 글리프 비트맵을 만들 때 사용하는 내장 고정폭 비트맵 폰트입니다.
+var glyphFace = basicfont.Face7x13
+
+// 내장 폰트의 글리프 셀 크기입니다.
+const (
+	glyphWidth  = 7
+	glyphHeight = 13
+)
+
+// hasGlyph는 내장 폰트가 주어진 문자의 글리프를 가지고 있는지 확인합니다.
+func hasGlyph(r rune) bool {
+	for _, rng := range glyphFace.Ranges {
+		if r >= rng.Low && r < rng.High {
+			return r != '�'
+		}
+	}
+	return false
+}
+
+// rasterizeGlyph는 문자를 glyphWidth x glyphHeight 크기의 회색조 비트맵으로 그립니다.
+// 값이 클수록 잉크가 많이 칠해진 픽셀입니다.
+func rasterizeGlyph(r rune) []uint8 {
+	img := image.NewGray(image.Rect(0, 0, glyphWidth, glyphHeight))
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.White,
+		Face: glyphFace,
+		Dot:  fixed.P(0, glyphFace.Ascent),
+	}
+	drawer.DrawString(string(r))
+	return img.Pix
+}
+
+// glyphCoverage는 글리프 셀에서 잉크가 차지하는 비율(0~1)을 반환합니다.
+func glyphCoverage(r rune) float64 {
+	sum := 0
+	pix := rasterizeGlyph(r)
+	for _, v := range pix {
+		sum += int(v)
+	}
+	return float64(sum) / 255.0 / float64(len(pix))
+}
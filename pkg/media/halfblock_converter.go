@@ -0,0 +1,96 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+func init() {
+	RegisterRenderer("halfblock", func(config types.PlayerConfig) (Renderer, error) {
+		return NewHalfBlockConverter(), nil
+	})
+}
+
+// HalfBlockRune은 위쪽 절반을 채우는 블록 문자입니다.
+// 전경색은 위쪽 픽셀, 배경색은 아래쪽 픽셀을 나타냅니다.
+const HalfBlockRune = '▀'
+
+// HalfBlockConverter는 한 셀에 두 개의 세로 픽셀을 담아 세로 해상도를 두 배로 높입니다.
+type HalfBlockConverter struct{}
+
+// NewHalfBlockConverter는 HalfBlockConverter 인스턴스를 생성합니다.
+func NewHalfBlockConverter() *HalfBlockConverter {
+	return &HalfBlockConverter{}
+}
+
+// Convert는 gocv.Mat을 전경색과 배경색이 지정된 ▀ 문자들의 프레임으로 변환합니다.
+// 원본 비율을 유지하도록 width x height 안에서 출력 크기를 맞추며,
+// color가 false이면 회색조로 출력합니다.
+func (c *HalfBlockConverter) Convert(img gocv.Mat, width, height int, color bool) (*Frame, error) {
+	originalWidth := img.Cols()
+	originalHeight := img.Rows()
+	if originalWidth == 0 || originalHeight == 0 {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", originalWidth, originalHeight)
+	}
+
+	cols, rows := fitCells(originalWidth, originalHeight, width, height)
+
+	// 셀 하나가 세로 픽셀 두 개를 표현하므로 높이를 두 배로 리사이즈합니다.
+	resized := gocv.NewMat()
+	defer resized.Close()
+	gocv.Resize(img, &resized, image.Pt(cols, rows*2), 0, 0, gocv.InterpolationArea)
+
+	channels := 3
+	data := resized.ToBytes()
+	if !color {
+		gray := gocv.NewMat()
+		defer gray.Close()
+		gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
+		data = gray.ToBytes()
+		channels = 1
+	}
+
+	// colorAt은 (x, y) 픽셀의 색상을 반환합니다.
+	colorAt := func(x, y int) Color {
+		offset := (y*cols + x) * channels
+		if channels == 1 {
+			return NewRGBColor(data[offset], data[offset], data[offset])
+		}
+		return NewRGBColor(data[offset+2], data[offset+1], data[offset])
+	}
+
+	frame := NewFrame(cols, rows)
+	var wg sync.WaitGroup
+	numWorkers := runtime.NumCPU()
+
+	rowJobs := make(chan int, rows)
+	for y := 0; y < rows; y++ {
+		rowJobs <- y
+	}
+	close(rowJobs)
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for y := range rowJobs {
+				row := frame.Row(y)
+				for x := 0; x < cols; x++ {
+					row[x] = Cell{
+						Rune: HalfBlockRune,
+						Fg:   colorAt(x, y*2),
+						Bg:   colorAt(x, y*2+1),
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return frame, nil
+}
@@ -0,0 +1,28 @@
+package media
+
+import "github.com/kweonminsung/console-cinema/pkg/types"
+
+// fitCells는 원본 비율을 유지하면서 width x height 셀 영역 안에 들어가는 최대 셀 크기를 계산합니다.
+// 터미널 문자의 세로/가로 비율은 types.YScaleFactor로 보정합니다.
+func fitCells(srcWidth, srcHeight, width, height int) (int, int) {
+	if srcWidth <= 0 || srcHeight <= 0 || width <= 0 || height <= 0 {
+		return width, height
+	}
+
+	aspectRatio := float64(srcHeight) / float64(srcWidth) * types.YScaleFactor
+	cols, rows := width, int(float64(width)*aspectRatio+0.5)
+	if rows > height {
+		rows = height
+		cols = int(float64(height)/aspectRatio + 0.5)
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	if cols > width {
+		cols = width
+	}
+	return cols, rows
+}
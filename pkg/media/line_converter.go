@@ -1,44 +1,68 @@
 package media
 
 import (
-	"bytes"
+	"fmt"
 	"image"
 	"math"
+	"runtime"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/kweonminsung/console-cinema/pkg/types"
 	"gocv.io/x/gocv"
 )
 
+func init() {
+	RegisterRenderer("line", func(config types.PlayerConfig) (Renderer, error) {
+		if config.LineCharset != "" && utf8.RuneCountInString(config.LineCharset) != len([]rune(LineCharset)) {
+			return nil, fmt.Errorf("line charset must have exactly %d characters (vertical, diagonal, horizontal, anti-diagonal), got %q",
+				len([]rune(LineCharset)), config.LineCharset)
+		}
+		return NewLineConverter(config.LineCharset, config.LineThreshold, config.LineFill), nil
+	})
+}
+
 // LineCharset은 라인 렌더링에 사용될 문자 집합입니다.
+// 순서대로 수직선, 대각선, 수평선, 역대각선을 나타냅니다.
 const LineCharset = `|/—\`
 
+// DefaultLineThreshold는 경계선으로 판단할 기본 그래디언트 크기입니다.
+const DefaultLineThreshold = 30.0
+
 // LineConverter는 이미지의 경계선을 감지하여 라인 문자로 변환하는 기능을 제공합니다.
 type LineConverter struct {
 	charset           []rune
 	gradientThreshold float64
+	fill              bool
+	fillCharset       []rune
 }
 
 // NewLineConverter는 새로운 LineConverter 인스턴스를 생성합니다.
-func NewLineConverter(charset string, threshold float64) *LineConverter {
+// fill이 true이면 경계선이 아닌 영역을 밝기 기반 ASCII 문자로 채웁니다.
+func NewLineConverter(charset string, threshold float64, fill bool) *LineConverter {
 	if charset == "" {
 		charset = LineCharset
 	}
 	if threshold <= 0 {
-		threshold = 30.0 // 기본 임계값
+		threshold = DefaultLineThreshold
 	}
 	return &LineConverter{
 		charset:           []rune(charset),
 		gradientThreshold: threshold,
+		fill:              fill,
+		fillCharset:       []rune(DefaultCharset),
 	}
 }
 
-// Convert는 gocv.Mat 이미지를 경계선 기반의 ASCII 문자로 변환합니다.
-func (c *LineConverter) Convert(img gocv.Mat, width, height int) (string, error) {
-	var buffer bytes.Buffer
-
+// Convert는 gocv.Mat 이미지를 경계선 기반의 ASCII 프레임으로 변환합니다.
+// color가 true이면 각 문자 아래에 있는 원본 픽셀의 색상을 사용합니다.
+func (c *LineConverter) Convert(img gocv.Mat, width, height int, color bool) (*Frame, error) {
 	// 1. 이미지 비율에 맞게 높이 재계산
 	originalWidth := float64(img.Cols())
 	originalHeight := float64(img.Rows())
+	if originalWidth == 0 || originalHeight == 0 {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", int(originalWidth), int(originalHeight))
+	}
 	aspectRatio := originalHeight / originalWidth
 	newHeight := int(float64(width) * aspectRatio * types.YScaleFactor)
 	if newHeight <= 0 {
@@ -66,46 +90,83 @@ func (c *LineConverter) Convert(img gocv.Mat, width, height int) (string, error)
 	gocv.Sobel(gray, &gradX, gocv.MatTypeCV16S, 1, 0, 3, 1, 0, gocv.BorderDefault)
 	gocv.Sobel(gray, &gradY, gocv.MatTypeCV16S, 0, 1, 3, 1, 0, gocv.BorderDefault)
 
-	// 4. 픽셀을 순회하며 각도에 따라 문자로 변환
+	// 4. 행 단위로 작업을 나누어 각 픽셀을 각도에 따라 문자로 변환
+	frame := NewFrame(width, newHeight)
+	var wg sync.WaitGroup
+	numWorkers := runtime.NumCPU()
+
+	rowJobs := make(chan int, newHeight)
 	for y := 0; y < newHeight; y++ {
-		for x := 0; x < width; x++ {
-			dx := float64(gradX.GetShortAt(y, x))
-			dy := float64(gradY.GetShortAt(y, x))
+		rowJobs <- y
+	}
+	close(rowJobs)
+
+	wg.Add(newHeight)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			for y := range rowJobs {
+				row := frame.Row(y)
+				for x := 0; x < width; x++ {
+					dx := float64(gradX.GetShortAt(y, x))
+					dy := float64(gradY.GetShortAt(y, x))
+
+					char, ok := c.edgeRune(dx, dy)
+					if !ok {
+						char = ' ' // 임계값보다 작으면 공백 처리
+						if c.fill {
+							char = c.fillRune(gray.GetUCharAt(y, x))
+						}
+					}
+
+					row[x].Rune = char
+					if color && char != ' ' {
+						vec := resized.GetVecbAt(y, x)
+						b, g, r := vec[0], vec[1], vec[2]
+						row[x].Fg = NewRGBColor(r, g, b)
+					}
+				}
+				wg.Done()
+			}
+		}()
+	}
+	wg.Wait()
 
-			// 그래디언트 크기 계산
-			magnitude := math.Sqrt(dx*dx + dy*dy)
+	return frame, nil
+}
 
-			if magnitude < c.gradientThreshold {
-				buffer.WriteRune(' ') // 임계값보다 작으면 공백 처리
-				continue
-			}
+// edgeRune은 그래디언트 방향에 맞는 라인 문자를 반환합니다.
+// 그래디언트 크기가 임계값보다 작으면 false를 반환합니다.
+func (c *LineConverter) edgeRune(dx, dy float64) (rune, bool) {
+	// 그래디언트 크기 계산
+	magnitude := math.Sqrt(dx*dx + dy*dy)
+	if magnitude < c.gradientThreshold {
+		return 0, false
+	}
 
-			// 각도 계산 (라디안)
-			angle := math.Atan2(dy, dx)
-			// 각도를 0-180도로 변환
-			angleDegrees := angle * (180.0 / math.Pi)
-			if angleDegrees < 0 {
-				angleDegrees += 180
-			}
+	// 각도 계산 후 0-180도로 변환
+	angleDegrees := math.Atan2(dy, dx) * (180.0 / math.Pi)
+	if angleDegrees < 0 {
+		angleDegrees += 180
+	}
 
-			// 각도에 따라 문자 선택
-			var char rune
-			switch {
-			case (angleDegrees >= 0 && angleDegrees < 22.5) || (angleDegrees >= 157.5 && angleDegrees <= 180):
-				char = '—' // 수평선
-			case angleDegrees >= 22.5 && angleDegrees < 67.5:
-				char = '/' // 대각선
-			case angleDegrees >= 67.5 && angleDegrees < 112.5:
-				char = '|' // 수직선
-			case angleDegrees >= 112.5 && angleDegrees < 157.5:
-				char = '\\' // 역대각선
-			default:
-				char = ' '
-			}
-			buffer.WriteRune(char)
-		}
-		buffer.WriteRune('\n')
+	// 그래디언트는 경계선에 수직이므로, 90도 회전한 방향의 문자를 선택합니다.
+	switch {
+	case angleDegrees < 22.5 || angleDegrees >= 157.5:
+		return c.charset[0], true // 수직선
+	case angleDegrees < 67.5:
+		return c.charset[1], true // 대각선
+	case angleDegrees < 112.5:
+		return c.charset[2], true // 수평선
+	default:
+		return c.charset[3], true // 역대각선
 	}
+}
 
-	return buffer.String(), nil
+// fillRune은 밝기 값에 해당하는 채움 문자를 반환합니다.
+func (c *LineConverter) fillRune(pixel uint8) rune {
+	idx := int(float64(pixel) / 255.0 * float64(len(c.fillCharset)))
+	if idx >= len(c.fillCharset) {
+		idx = len(c.fillCharset) - 1
+	}
+	return c.fillCharset[idx]
 }
@@ -0,0 +1,301 @@
+package media
+
+import (
+	"fmt"
+	"math"
+)
+
+// ColorDepth는 터미널이 표현할 수 있는 색상 수준입니다.
+type ColorDepth string
+
+const (
+	ColorDepthAuto      ColorDepth = "auto"
+	ColorDepthTrueColor ColorDepth = "truecolor"
+	ColorDepth256       ColorDepth = "256"
+	ColorDepth16        ColorDepth = "16"
+	ColorDepth8         ColorDepth = "8"
+	ColorDepthMono      ColorDepth = "mono"
+)
+
+// ColorDepths는 --colors에서 선택할 수 있는 모든 색상 수준입니다.
+var ColorDepths = []ColorDepth{ColorDepthAuto, ColorDepthTrueColor, ColorDepth256, ColorDepth16, ColorDepth8, ColorDepthMono}
+
+// ParseColorDepth는 문자열을 ColorDepth로 변환합니다. 빈 문자열은 자동 감지로 처리합니다.
+func ParseColorDepth(s string) (ColorDepth, error) {
+	if s == "" {
+		return ColorDepthAuto, nil
+	}
+	for _, depth := range ColorDepths {
+		if ColorDepth(s) == depth {
+			return depth, nil
+		}
+	}
+	return "", fmt.Errorf("unknown color depth %q (available: %v)", s, ColorDepths)
+}
+
+// ColorDepthFromCount는 터미널이 보고한 색상 수에 맞는 ColorDepth를 반환합니다.
+func ColorDepthFromCount(colors int) ColorDepth {
+	switch {
+	case colors >= 1<<24:
+		return ColorDepthTrueColor
+	case colors >= 256:
+		return ColorDepth256
+	case colors >= 16:
+		return ColorDepth16
+	case colors >= 8:
+		return ColorDepth8
+	default:
+		return ColorDepthMono
+	}
+}
+
+// ansiColors는 xterm 기본 테마의 16가지 시스템 색상입니다.
+var ansiColors = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0x80, 0x00, 0x00}, {0x00, 0x80, 0x00}, {0x80, 0x80, 0x00},
+	{0x00, 0x00, 0x80}, {0x80, 0x00, 0x80}, {0x00, 0x80, 0x80}, {0xc0, 0xc0, 0xc0},
+	{0x80, 0x80, 0x80}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x00, 0x00, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// paletteColors는 색상 수준에 해당하는 팔레트를 반환합니다.
+// 팔레트의 인덱스는 터미널의 색상 번호와 같습니다. 흑백 팔레트는 검정(0)과 흰색(1)입니다.
+func paletteColors(depth ColorDepth) [][3]uint8 {
+	switch depth {
+	case ColorDepthMono:
+		return [][3]uint8{ansiColors[0], ansiColors[15]}
+	case ColorDepth8:
+		return append([][3]uint8{}, ansiColors[:8]...)
+	case ColorDepth16:
+		return append([][3]uint8{}, ansiColors[:]...)
+	default:
+		colors := append([][3]uint8{}, ansiColors[:]...)
+		// 6x6x6 컬러 큐브
+		levels := [6]uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+		for r := 0; r < 6; r++ {
+			for g := 0; g < 6; g++ {
+				for b := 0; b < 6; b++ {
+					colors = append(colors, [3]uint8{levels[r], levels[g], levels[b]})
+				}
+			}
+		}
+		// 24단계 회색조
+		for i := 0; i < 24; i++ {
+			v := uint8(8 + i*10)
+			colors = append(colors, [3]uint8{v, v, v})
+		}
+		return colors
+	}
+}
+
+// lab은 CIELAB 색 공간의 색상입니다.
+type lab struct {
+	l, a, b float64
+}
+
+// toLab은 sRGB 색상을 D65 기준의 CIELAB 색상으로 변환합니다.
+func toLab(r, g, b uint8) lab {
+	linear := func(v uint8) float64 {
+		c := float64(v) / 255.0
+		if c <= 0.04045 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	lr, lg, lb := linear(r), linear(g), linear(b)
+
+	x := (0.4124*lr + 0.3576*lg + 0.1805*lb) / 0.95047
+	y := 0.2126*lr + 0.7152*lg + 0.0722*lb
+	z := (0.0193*lr + 0.1192*lg + 0.9505*lb) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389.0 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27.0*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+
+	return lab{l: 116*fy - 16, a: 500 * (fx - fy), b: 200 * (fy - fz)}
+}
+
+// quantizerBits는 가장 가까운 색상 캐시에 사용하는 채널당 비트 수입니다.
+const quantizerBits = 6
+
+// Quantizer는 RGB 색상을 CIELAB 거리 기준으로 가장 가까운 팔레트 색상에 매핑합니다.
+// 내부 캐시를 사용하므로 여러 고루틴에서 동시에 사용하면 안 됩니다.
+type Quantizer struct {
+	depth   ColorDepth
+	dither  bool
+	colors  [][3]uint8
+	labs    []lab
+	nearest []int16
+}
+
+// NewQuantizer는 주어진 색상 수준의 팔레트로 Quantizer를 생성합니다.
+// dither가 true이면 QuantizeFrame이 Floyd–Steinberg 오차 확산을 사용합니다.
+func NewQuantizer(depth ColorDepth, dither bool) *Quantizer {
+	colors := paletteColors(depth)
+	labs := make([]lab, len(colors))
+	for i, c := range colors {
+		labs[i] = toLab(c[0], c[1], c[2])
+	}
+
+	nearest := make([]int16, 1<<(3*quantizerBits))
+	for i := range nearest {
+		nearest[i] = -1
+	}
+
+	return &Quantizer{
+		depth:   depth,
+		dither:  dither,
+		colors:  colors,
+		labs:    labs,
+		nearest: nearest,
+	}
+}
+
+// Depth는 Quantizer의 색상 수준을 반환합니다.
+func (q *Quantizer) Depth() ColorDepth {
+	return q.depth
+}
+
+// PaletteColor는 팔레트 인덱스의 RGB 값을 반환합니다.
+func (q *Quantizer) PaletteColor(index int) (uint8, uint8, uint8) {
+	c := q.colors[index]
+	return c[0], c[1], c[2]
+}
+
+// Nearest는 주어진 색상과 CIELAB 거리가 가장 가까운 팔레트 인덱스를 반환합니다.
+func (q *Quantizer) Nearest(r, g, b uint8) int {
+	const shift = 8 - quantizerBits
+	key := int(r>>shift)<<(2*quantizerBits) | int(g>>shift)<<quantizerBits | int(b>>shift)
+	if idx := q.nearest[key]; idx >= 0 {
+		return int(idx)
+	}
+
+	// 캐시 버킷의 중앙값으로 검색해 같은 버킷의 모든 색상이 같은 결과를 갖도록 합니다.
+	const half = 1 << shift >> 1
+	target := toLab(r>>shift<<shift|half, g>>shift<<shift|half, b>>shift<<shift|half)
+	best, bestDist := 0, math.MaxFloat64
+	for i, c := range q.labs {
+		dl, da, db := target.l-c.l, target.a-c.a, target.b-c.b
+		if dist := dl*dl + da*da + db*db; dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	q.nearest[key] = int16(best)
+	return best
+}
+
+// QuantizeFrame은 프레임의 모든 RGB 색상을 팔레트 인덱스 색상으로 바꾼 새 프레임을 반환합니다.
+// 흑백 팔레트에서는 밝은 색을 터미널 기본 전경색으로 보고, 밝은 배경은 반전 속성으로 표현합니다.
+func (q *Quantizer) QuantizeFrame(frame *Frame) *Frame {
+	fg := q.quantizeGrid(frame, func(cell Cell) Color { return cell.Fg })
+	bg := q.quantizeGrid(frame, func(cell Cell) Color { return cell.Bg })
+
+	out := frame.Clone()
+	for i := range out.Cells {
+		cell := &out.Cells[i]
+		if q.depth == ColorDepthMono {
+			quantizeMonoCell(cell, fg[i], bg[i])
+			continue
+		}
+		if fg[i] >= 0 {
+			cell.Fg = NewIndexColor(fg[i])
+		}
+		if bg[i] >= 0 {
+			cell.Bg = NewIndexColor(bg[i])
+		}
+	}
+	return out
+}
+
+// quantizeMonoCell은 흑백 팔레트 인덱스(0: 꺼짐, 1: 켜짐)에 맞게 셀의 문자와 속성을 바꿉니다.
+// 색상이 없는 전경(-1)은 켜진 것으로 봅니다.
+func quantizeMonoCell(cell *Cell, fg, bg int) {
+	fgLit := fg != 0
+	hasBg := bg >= 0
+	cell.Fg, cell.Bg = ColorDefault, ColorDefault
+
+	if !hasBg {
+		if !fgLit {
+			cell.Rune = ' '
+		}
+		return
+	}
+
+	bgLit := bg == 1
+	switch {
+	case fgLit && bgLit:
+		cell.Rune = '█'
+	case !fgLit && !bgLit:
+		cell.Rune = ' '
+	case bgLit:
+		cell.Attrs |= AttrReverse
+	}
+}
+
+// quantizeGrid는 프레임의 각 셀에서 colorOf로 고른 색상을 팔레트 인덱스로 변환합니다.
+// RGB 색상이 아닌 칸은 -1을 반환하며 오차 확산에서도 제외됩니다.
+func (q *Quantizer) quantizeGrid(frame *Frame, colorOf func(Cell) Color) []int {
+	indices := make([]int, len(frame.Cells))
+
+	if !q.dither {
+		for i, cell := range frame.Cells {
+			indices[i] = -1
+			if c := colorOf(cell); c.IsRGB() {
+				indices[i] = q.Nearest(c.RGB())
+			}
+		}
+		return indices
+	}
+
+	clamp := func(v float64) uint8 {
+		if v < 0 {
+			return 0
+		}
+		if v > 255 {
+			return 255
+		}
+		return uint8(v + 0.5)
+	}
+
+	// 오차를 오른쪽과 아래 행으로 확산시키기 위해 두 행 분량의 버퍼만 유지합니다.
+	width, height := frame.Width, frame.Height
+	cur := make([][3]float64, width+2)
+	next := make([][3]float64, width+2)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			c := colorOf(frame.Cells[i])
+			if !c.IsRGB() {
+				indices[i] = -1
+				continue
+			}
+
+			r, g, b := c.RGB()
+			var want [3]float64
+			var adjusted [3]uint8
+			for ch, v := range [3]uint8{r, g, b} {
+				want[ch] = float64(v) + cur[x+1][ch]
+				adjusted[ch] = clamp(want[ch])
+			}
+			idx := q.Nearest(adjusted[0], adjusted[1], adjusted[2])
+			indices[i] = idx
+
+			for ch := 0; ch < 3; ch++ {
+				quantErr := want[ch] - float64(q.colors[idx][ch])
+				cur[x+2][ch] += quantErr * 7 / 16
+				next[x][ch] += quantErr * 3 / 16
+				next[x+1][ch] += quantErr * 5 / 16
+				next[x+2][ch] += quantErr * 1 / 16
+			}
+		}
+		cur, next = next, cur
+		for i := range next {
+			next[i] = [3]float64{}
+		}
+	}
+
+	return indices
+}
@@ -0,0 +1,178 @@
+package media
+
+import (
+	"testing"
+)
+
+func TestParseColorDepth(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ColorDepth
+		wantErr bool
+	}{
+		{"", ColorDepthAuto, false},
+		{"auto", ColorDepthAuto, false},
+		{"truecolor", ColorDepthTrueColor, false},
+		{"256", ColorDepth256, false},
+		{"16", ColorDepth16, false},
+		{"8", ColorDepth8, false},
+		{"mono", ColorDepthMono, false},
+		{"24bit", "", true},
+	}
+	for _, test := range tests {
+		got, err := ParseColorDepth(test.input)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("ParseColorDepth(%q) = %q, %v", test.input, got, err)
+		}
+	}
+}
+
+func TestColorDepthFromCount(t *testing.T) {
+	tests := []struct {
+		colors int
+		want   ColorDepth
+	}{
+		{0, ColorDepthMono},
+		{2, ColorDepthMono},
+		{8, ColorDepth8},
+		{16, ColorDepth16},
+		{88, ColorDepth16},
+		{256, ColorDepth256},
+		{1 << 24, ColorDepthTrueColor},
+	}
+	for _, test := range tests {
+		if got := ColorDepthFromCount(test.colors); got != test.want {
+			t.Errorf("ColorDepthFromCount(%d) = %q, want %q", test.colors, got, test.want)
+		}
+	}
+}
+
+func TestQuantizerKeepsPaletteColors(t *testing.T) {
+	// 256색 팔레트에는 캐시 버킷 하나 차이로 붙어 있는 회색이 있어
+	// 버킷 너비만큼의 차이는 허용합니다.
+	tolerance := map[ColorDepth]int{ColorDepthMono: 0, ColorDepth8: 0, ColorDepth16: 0, ColorDepth256: 1 << (8 - quantizerBits)}
+	for depth, tol := range tolerance {
+		q := NewQuantizer(depth, false)
+		for i, c := range q.colors {
+			r, g, b := q.PaletteColor(q.Nearest(c[0], c[1], c[2]))
+			got := [3]uint8{r, g, b}
+			for ch := range c {
+				if abs(int(got[ch])-int(c[ch])) > tol {
+					t.Errorf("%s: palette color %d %v maps to %v", depth, i, c, got)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestQuantizerNearest(t *testing.T) {
+	tests := []struct {
+		depth   ColorDepth
+		r, g, b uint8
+		want    int
+	}{
+		{ColorDepthMono, 30, 30, 30, 0},
+		{ColorDepthMono, 220, 220, 220, 1},
+		{ColorDepth8, 250, 10, 10, 1},
+		{ColorDepth16, 250, 10, 10, 9},
+		{ColorDepth16, 10, 10, 140, 4},
+		{ColorDepth256, 0xd7, 0x5f, 0x00, 16 + 36*4 + 6*1},
+		{ColorDepth256, 0x4e, 0x4e, 0x4e, 239},
+	}
+	for _, test := range tests {
+		q := NewQuantizer(test.depth, false)
+		if got := q.Nearest(test.r, test.g, test.b); got != test.want {
+			t.Errorf("%s: Nearest(%d, %d, %d) = %d, want %d", test.depth, test.r, test.g, test.b, got, test.want)
+		}
+		// 캐시된 결과도 같아야 합니다.
+		if got := q.Nearest(test.r, test.g, test.b); got != test.want {
+			t.Errorf("%s: cached Nearest(%d, %d, %d) = %d, want %d", test.depth, test.r, test.g, test.b, got, test.want)
+		}
+	}
+}
+
+func TestQuantizeFrame(t *testing.T) {
+	q := NewQuantizer(ColorDepth16, false)
+	frame := NewFrame(3, 1)
+	frame.Set(0, 0, Cell{Rune: 'a', Fg: NewRGBColor(0xff, 0, 0), Bg: NewRGBColor(0, 0, 0xff)})
+	frame.Set(1, 0, Cell{Rune: 'b', Fg: NewIndexColor(3)})
+	frame.Set(2, 0, Cell{Rune: 'c'})
+
+	out := q.QuantizeFrame(frame)
+	want := []Cell{
+		{Rune: 'a', Fg: NewIndexColor(9), Bg: NewIndexColor(12)},
+		{Rune: 'b', Fg: NewIndexColor(3)},
+		{Rune: 'c'},
+	}
+	for x, cell := range want {
+		if got := out.At(x, 0); got != cell {
+			t.Errorf("cell %d is %+v, want %+v", x, got, cell)
+		}
+	}
+	if frame.At(0, 0).Fg != NewRGBColor(0xff, 0, 0) {
+		t.Error("QuantizeFrame changed the original frame")
+	}
+}
+
+func TestQuantizeFrameMono(t *testing.T) {
+	dark := NewRGBColor(20, 20, 20)
+	light := NewRGBColor(235, 235, 235)
+	tests := []struct {
+		name string
+		cell Cell
+		want Cell
+	}{
+		{"light text", Cell{Rune: 'a', Fg: light}, Cell{Rune: 'a'}},
+		{"dark text", Cell{Rune: 'a', Fg: dark}, Cell{Rune: ' '}},
+		{"both light", Cell{Rune: '▀', Fg: light, Bg: light}, Cell{Rune: '█'}},
+		{"both dark", Cell{Rune: '▀', Fg: dark, Bg: dark}, Cell{Rune: ' '}},
+		{"light background", Cell{Rune: '▀', Fg: dark, Bg: light}, Cell{Rune: '▀', Attrs: AttrReverse}},
+		{"light foreground", Cell{Rune: '▀', Fg: light, Bg: dark}, Cell{Rune: '▀'}},
+	}
+	q := NewQuantizer(ColorDepthMono, false)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			frame := NewFrame(1, 1)
+			frame.Set(0, 0, test.cell)
+			if got := q.QuantizeFrame(frame).At(0, 0); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestQuantizeFrameDither(t *testing.T) {
+	const size = 16
+	gray := NewRGBColor(128, 128, 128)
+	frame := NewFrame(size, size)
+	for i := range frame.Cells {
+		frame.Cells[i] = Cell{Rune: ' ', Bg: gray}
+	}
+
+	count := func(q *Quantizer) int {
+		lit := 0
+		for _, cell := range q.QuantizeFrame(frame).Cells {
+			if cell.Rune == '█' {
+				lit++
+			}
+		}
+		return lit
+	}
+
+	// 오차 확산 없이 고른 회색은 한 가지 색으로만 칠해집니다.
+	if lit := count(NewQuantizer(ColorDepthMono, false)); lit != 0 && lit != size*size {
+		t.Errorf("%d of %d cells are lit without dithering", lit, size*size)
+	}
+	// 오차 확산을 쓰면 밝기에 비례해 절반 정도가 켜집니다.
+	if lit := count(NewQuantizer(ColorDepthMono, true)); lit < size*size*2/5 || lit > size*size*3/5 {
+		t.Errorf("%d of %d cells are lit with dithering, want about half", lit, size*size)
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
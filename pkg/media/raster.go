@@ -0,0 +1,228 @@
+package media
+
+import (
+	"image"
+	"image/color"
+)
+
+// DefaultFontSize는 내장 폰트의 원래 글리프 높이로, 래스터화할 때 기본 셀 높이(픽셀)입니다.
+const DefaultFontSize = glyphHeight
+
+// 기본 색상으로 지정된 셀을 그릴 때 사용하는 터미널 기본 전경색과 배경색입니다.
+var (
+	rasterDefaultFg = color.RGBA{0xe5, 0xe5, 0xe5, 0xff}
+	rasterDefaultBg = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+// brailleDotRadius는 점자 점의 반지름으로, 점 하나가 차지하는 하위 칸 크기에 대한 비율입니다.
+const brailleDotRadius = 0.4
+
+// xtermColors는 팔레트 인덱스 색상을 RGB로 되돌릴 때 사용하는 xterm 256색 팔레트입니다.
+var xtermColors = paletteColors(ColorDepth256)
+
+// Rasterizer는 프레임을 터미널에 보이는 모습 그대로 이미지로 그립니다.
+// 문자는 내장 비트맵 폰트로, 블록·점자·육분면 문자는 도형으로 직접 그리므로
+// 픽셀 모드의 셀은 색이 채워진 블록이 됩니다.
+// 글리프 캐시를 사용하므로 여러 고루틴에서 동시에 사용하면 안 됩니다.
+type Rasterizer struct {
+	cellWidth  int
+	cellHeight int
+	// masks는 문자별로 셀의 각 픽셀을 전경색이 덮는 정도(0~255)를 저장합니다.
+	masks map[rune][]uint8
+}
+
+// NewRasterizer는 셀 높이가 fontSize 픽셀인 Rasterizer를 생성합니다.
+// 셀 너비는 내장 폰트의 가로세로 비율을 따르며, fontSize가 0 이하이면 DefaultFontSize를 사용합니다.
+func NewRasterizer(fontSize int) *Rasterizer {
+	if fontSize <= 0 {
+		fontSize = DefaultFontSize
+	}
+	cellWidth := (fontSize*glyphWidth + glyphHeight/2) / glyphHeight
+	if cellWidth < 1 {
+		cellWidth = 1
+	}
+	return &Rasterizer{
+		cellWidth:  cellWidth,
+		cellHeight: fontSize,
+		masks:      make(map[rune][]uint8),
+	}
+}
+
+// CellSize는 한 셀의 픽셀 크기를 반환합니다.
+func (r *Rasterizer) CellSize() (int, int) {
+	return r.cellWidth, r.cellHeight
+}
+
+// Draw는 프레임을 셀 크기에 맞는 RGBA 이미지로 그립니다.
+func (r *Rasterizer) Draw(frame *Frame) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, frame.Width*r.cellWidth, frame.Height*r.cellHeight))
+	for y := 0; y < frame.Height; y++ {
+		for x, cell := range frame.Row(y) {
+			r.drawCell(img, x*r.cellWidth, y*r.cellHeight, cell)
+		}
+	}
+	return img
+}
+
+// drawCell은 (x0, y0)에서 시작하는 셀 영역에 하나의 셀을 그립니다.
+func (r *Rasterizer) drawCell(img *image.RGBA, x0, y0 int, cell Cell) {
+	fg, bg := rasterColor(cell.Fg, rasterDefaultFg), rasterColor(cell.Bg, rasterDefaultBg)
+	if cell.Attrs&AttrReverse != 0 {
+		fg, bg = bg, fg
+	}
+
+	mask := r.mask(cell.Rune, cell.Attrs&AttrBold != 0)
+	for py := 0; py < r.cellHeight; py++ {
+		offset := img.PixOffset(x0, y0+py)
+		for px := 0; px < r.cellWidth; px++ {
+			a := uint32(mask[py*r.cellWidth+px])
+			pix := img.Pix[offset+px*4 : offset+px*4+4]
+			pix[0] = uint8((uint32(fg.R)*a + uint32(bg.R)*(255-a)) / 255)
+			pix[1] = uint8((uint32(fg.G)*a + uint32(bg.G)*(255-a)) / 255)
+			pix[2] = uint8((uint32(fg.B)*a + uint32(bg.B)*(255-a)) / 255)
+			pix[3] = 0xff
+		}
+	}
+}
+
+// rasterColor는 셀 색상을 RGB로 변환합니다. 기본 색상은 fallback을 사용합니다.
+func rasterColor(c Color, fallback color.RGBA) color.RGBA {
+	switch {
+	case c.IsRGB():
+		red, green, blue := c.RGB()
+		return color.RGBA{red, green, blue, 0xff}
+	case c.IsIndex():
+		rgb := xtermColors[c.Index()]
+		return color.RGBA{rgb[0], rgb[1], rgb[2], 0xff}
+	default:
+		return fallback
+	}
+}
+
+// mask는 문자의 덮임 마스크를 캐시에서 찾거나 새로 만듭니다.
+// 굵은 글자는 폰트 글리프를 한 픽셀 오른쪽으로 겹쳐 그립니다.
+func (r *Rasterizer) mask(ch rune, bold bool) []uint8 {
+	key := ch
+	if bold {
+		// 유니코드 범위 밖의 값을 굵은 글자의 캐시 키로 사용합니다.
+		key = ch | 1<<30
+	}
+	if mask, ok := r.masks[key]; ok {
+		return mask
+	}
+
+	mask := make([]uint8, r.cellWidth*r.cellHeight)
+	if !r.fillShapeMask(mask, ch) {
+		r.fillGlyphMask(mask, ch, bold)
+	}
+	r.masks[key] = mask
+	return mask
+}
+
+// fillGlyphMask는 내장 폰트의 글리프를 셀 크기로 확대/축소하여 마스크에 그립니다.
+// 폰트에 없는 문자는 빈 칸으로 남습니다.
+func (r *Rasterizer) fillGlyphMask(mask []uint8, ch rune, bold bool) {
+	if !hasGlyph(ch) {
+		return
+	}
+	glyph := rasterizeGlyph(ch)
+	for py := 0; py < r.cellHeight; py++ {
+		gy := py * glyphHeight / r.cellHeight
+		for px := 0; px < r.cellWidth; px++ {
+			gx := px * glyphWidth / r.cellWidth
+			v := glyph[gy*glyphWidth+gx]
+			if bold && gx > 0 && glyph[gy*glyphWidth+gx-1] > v {
+				v = glyph[gy*glyphWidth+gx-1]
+			}
+			mask[py*r.cellWidth+px] = v
+		}
+	}
+}
+
+// fillShapeMask는 블록, 점자, 육분면 문자를 도형으로 그립니다.
+// 도형으로 그릴 수 없는 문자이면 false를 반환합니다.
+func (r *Rasterizer) fillShapeMask(mask []uint8, ch rune) bool {
+	coverage := shapeCoverage(ch)
+	if coverage == nil {
+		return false
+	}
+	for py := 0; py < r.cellHeight; py++ {
+		fy := (float64(py) + 0.5) / float64(r.cellHeight)
+		for px := 0; px < r.cellWidth; px++ {
+			fx := (float64(px) + 0.5) / float64(r.cellWidth)
+			mask[py*r.cellWidth+px] = uint8(coverage(fx, fy)*255 + 0.5)
+		}
+	}
+	return true
+}
+
+// shapeCoverage는 셀 안의 상대 위치(0~1)에서 전경색이 덮는 비율을 계산하는 함수를 반환합니다.
+// 도형으로 그리는 문자가 아니면 nil을 반환합니다.
+func shapeCoverage(ch rune) func(fx, fy float64) float64 {
+	solid := func(inside func(fx, fy float64) bool) func(fx, fy float64) float64 {
+		return func(fx, fy float64) float64 {
+			if inside(fx, fy) {
+				return 1
+			}
+			return 0
+		}
+	}
+
+	switch {
+	case ch == ' ':
+		return func(fx, fy float64) float64 { return 0 }
+	case ch == '░', ch == '▒', ch == '▓':
+		shade := float64(ch-'░'+1) / 4
+		return func(fx, fy float64) float64 { return shade }
+	case ch >= '▁' && ch <= '█':
+		// 아래에서부터 1/8씩 채워지는 블록입니다.
+		eighths := float64(ch-'▁'+1) / 8
+		return solid(func(fx, fy float64) bool { return fy >= 1-eighths })
+	case ch >= '▉' && ch <= '▏':
+		// 왼쪽에서부터 7/8부터 1/8까지 채워지는 블록입니다.
+		eighths := float64('▏'-ch+1) / 8
+		return solid(func(fx, fy float64) bool { return fx < eighths })
+	case ch == '▀':
+		return solid(func(fx, fy float64) bool { return fy < 0.5 })
+	case ch == '▐':
+		return solid(func(fx, fy float64) bool { return fx >= 0.5 })
+	case ch == '▔':
+		return solid(func(fx, fy float64) bool { return fy < 0.125 })
+	case ch == '▕':
+		return solid(func(fx, fy float64) bool { return fx >= 0.875 })
+	case ch >= brailleBase && ch <= brailleBase+0xff:
+		pattern := ch - brailleBase
+		return solid(func(fx, fy float64) bool {
+			cx, cy := fx*2, fy*4
+			dx, dy := int(cx), int(cy)
+			if pattern&brailleDots[dy][dx] == 0 {
+				return false
+			}
+			// 점은 하위 칸의 가운데에 작게 찍힙니다.
+			ox, oy := cx-float64(dx)-0.5, cy-float64(dy)-0.5
+			return ox*ox+oy*oy <= brailleDotRadius*brailleDotRadius
+		})
+	}
+
+	if pattern, ok := glyphPattern(quadrantGlyphs, ch); ok {
+		return solid(func(fx, fy float64) bool {
+			return pattern&(1<<(int(fy*2)*2+int(fx*2))) != 0
+		})
+	}
+	if pattern, ok := glyphPattern(sextantGlyphs, ch); ok {
+		return solid(func(fx, fy float64) bool {
+			return pattern&(1<<(int(fy*3)*2+int(fx*2))) != 0
+		})
+	}
+	return nil
+}
+
+// glyphPattern은 패턴 표에서 문자의 하위 픽셀 비트 패턴을 찾습니다.
+func glyphPattern(glyphs []rune, ch rune) (int, bool) {
+	for pattern, glyph := range glyphs {
+		if glyph == ch {
+			return pattern, true
+		}
+	}
+	return 0, false
+}
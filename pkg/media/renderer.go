@@ -0,0 +1,63 @@
+package media
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+// Renderer는 디코딩된 프레임을 터미널에 출력할 셀 격자(Frame)로 변환합니다.
+// 새로운 렌더링 방식은 이 인터페이스를 구현한 뒤 RegisterRenderer로 등록하면 됩니다.
+type Renderer interface {
+	Convert(img gocv.Mat, width, height int, color bool) (*Frame, error)
+}
+
+// RendererFactory는 플레이어 설정으로부터 Renderer를 생성합니다.
+type RendererFactory func(config types.PlayerConfig) (Renderer, error)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = make(map[string]RendererFactory)
+)
+
+// RegisterRenderer는 주어진 이름으로 Renderer 생성 함수를 등록합니다.
+// 보통 각 컨버터 파일의 init에서 호출되며, 같은 이름을 두 번 등록하면 panic이 발생합니다.
+func RegisterRenderer(name string, factory RendererFactory) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	if factory == nil {
+		panic("media: RegisterRenderer factory is nil")
+	}
+	if _, dup := renderers[name]; dup {
+		panic("media: RegisterRenderer called twice for renderer " + name)
+	}
+	renderers[name] = factory
+}
+
+// NewRenderer는 등록된 이름에 해당하는 Renderer를 생성합니다.
+func NewRenderer(name string, config types.PlayerConfig) (Renderer, error) {
+	renderersMu.RLock()
+	factory, ok := renderers[name]
+	renderersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown render mode %q (available: %v)", name, RendererNames())
+	}
+	return factory(config)
+}
+
+// RendererNames는 등록된 모든 Renderer의 이름을 정렬하여 반환합니다.
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,109 @@
+package media
+
+import (
+	"fmt"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// SeekMode는 FrameExtractor가 재생 위치를 옮기는 방식입니다.
+type SeekMode string
+
+const (
+	// SeekAccurate는 목표 이전의 키프레임부터 디코딩하여 정확한 프레임에 도달합니다.
+	SeekAccurate SeekMode = "accurate"
+	// SeekFast는 디코더에 위치만 지정하여 빠르지만 근처의 키프레임에 도달할 수 있습니다.
+	SeekFast SeekMode = "fast"
+)
+
+// SeekModes는 지원하는 모든 탐색 방식입니다.
+var SeekModes = []SeekMode{SeekAccurate, SeekFast}
+
+const (
+	// seekPreroll은 정확한 탐색에서 목표보다 먼저 이동하는 최초 시간입니다.
+	// 디코더는 이 위치 이전의 키프레임에 도달하므로, 그곳부터 목표까지 디코딩합니다.
+	seekPreroll = time.Second
+	// maxSeekAttempts는 키프레임이 목표를 지나쳤을 때 preroll을 두 배로 늘려 다시 시도하는 횟수입니다.
+	maxSeekAttempts = 4
+	// maxSeekDecodeFrames는 정확한 탐색 한 번에 디코딩하는 최대 프레임 수입니다.
+	maxSeekDecodeFrames = 1200
+)
+
+// ParseSeekMode는 문자열을 SeekMode로 변환합니다. 빈 문자열은 정확한 탐색으로 처리합니다.
+func ParseSeekMode(s string) (SeekMode, error) {
+	if s == "" {
+		return SeekAccurate, nil
+	}
+	for _, mode := range SeekModes {
+		if SeekMode(s) == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown seek mode %q (available: %v)", s, SeekModes)
+}
+
+// SetSeekMode는 이후의 Seek 호출에 사용할 탐색 방식을 지정합니다.
+func (c *FrameExtractor) SetSeekMode(mode SeekMode) {
+	c.seekMode = mode
+}
+
+// seekFast는 디코더에 목표 시간만 지정합니다.
+func (c *FrameExtractor) seekFast(d time.Duration) {
+	c.vc.Set(gocv.VideoCapturePosMsec, float64(d.Milliseconds()))
+}
+
+// seekAccurate는 다음에 읽는 프레임이 목표 시간의 프레임이 되도록 이동합니다.
+// 목표보다 preroll만큼 앞으로 이동한 뒤 목표 직전 프레임까지 디코딩하며 건너뜁니다.
+// 디코더가 목표를 지나친 위치에 도착하면 preroll을 늘려 다시 시도하고,
+// 그래도 실패하거나 디코딩이 멈추면 빠른 탐색으로 대체합니다.
+func (c *FrameExtractor) seekAccurate(d time.Duration) error {
+	if c.fps <= 0 {
+		c.seekFast(d)
+		return nil
+	}
+	targetFrame := int(d.Seconds()*c.fps + 0.5)
+
+	preroll := seekPreroll
+	for attempt := 0; attempt < maxSeekAttempts; attempt++ {
+		start := d - preroll
+		if start < 0 {
+			start = 0
+		}
+		c.seekFast(start)
+
+		// 디코더가 키프레임을 찾다가 목표를 지나쳤다면 더 앞에서 다시 시작합니다.
+		if c.GetCurrentFrame() > targetFrame && start > 0 {
+			preroll *= 2
+			continue
+		}
+
+		if c.decodeForwardTo(targetFrame) {
+			return nil
+		}
+		break
+	}
+
+	c.seekFast(d)
+	return fmt.Errorf("accurate seek to %v failed, fell back to a fast seek", d)
+}
+
+// decodeForwardTo는 다음에 읽을 프레임 번호가 targetFrame이 될 때까지 프레임을 건너뜁니다.
+// 디코딩이 더 이상 진행되지 않거나 너무 많은 프레임이 필요하면 false를 반환합니다.
+func (c *FrameExtractor) decodeForwardTo(targetFrame int) bool {
+	current := c.GetCurrentFrame()
+	for skipped := 0; current < targetFrame; skipped++ {
+		if skipped >= maxSeekDecodeFrames {
+			return false
+		}
+		if err := c.vc.Grab(1); err != nil {
+			return false
+		}
+		next := c.GetCurrentFrame()
+		if next <= current {
+			return false // 스트림의 끝이거나 디코더가 멈췄습니다
+		}
+		current = next
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+func init() {
+	RegisterRenderer("shape", func(config types.PlayerConfig) (Renderer, error) {
+		return NewShapeConverter(), nil
+	})
+}
+
+// shapeGlyph는 글리프 매칭에 사용하는 미리 래스터화된 문자 비트맵입니다.
+// 내장 비트맵 폰트는 픽셀이 켜짐/꺼짐 두 값뿐이므로 잉크가 칠해진 픽셀의 위치만 저장합니다.
+type shapeGlyph struct {
+	char rune
+	ink  []int
+	// norm은 비트맵 픽셀 값의 제곱합으로, 거리 계산 시 재사용됩니다.
+	norm int
+}
+
+// ShapeConverter는 각 셀의 축소된 이미지 조각을 글리프 비트맵과 비교하여
+// 밝기뿐 아니라 모양이 가장 비슷한 문자를 선택합니다.
+type ShapeConverter struct {
+	glyphs []shapeGlyph
+}
+
+// NewShapeConverter는 내장 폰트의 출력 가능한 ASCII 문자로 ShapeConverter를 생성합니다.
+func NewShapeConverter() *ShapeConverter {
+	var glyphs []shapeGlyph
+	for r := rune(0x20); r < 0x7f; r++ {
+		glyph := shapeGlyph{char: r}
+		for i, v := range rasterizeGlyph(r) {
+			if v >= 128 {
+				glyph.ink = append(glyph.ink, i)
+			}
+		}
+		glyph.norm = len(glyph.ink) * 255 * 255
+		glyphs = append(glyphs, glyph)
+	}
+	return &ShapeConverter{glyphs: glyphs}
+}
+
+// Convert는 gocv.Mat을 모양 기반 ASCII 프레임으로 변환합니다.
+// color가 true이면 각 셀의 평균 색상을 전경색으로 사용합니다.
+func (c *ShapeConverter) Convert(img gocv.Mat, width, height int, color bool) (*Frame, error) {
+	originalWidth := img.Cols()
+	originalHeight := img.Rows()
+	if originalWidth == 0 || originalHeight == 0 {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", originalWidth, originalHeight)
+	}
+
+	cols, rows := fitCells(originalWidth, originalHeight, width, height)
+
+	// 각 셀이 글리프 하나와 같은 크기의 조각이 되도록 리사이즈합니다.
+	patchWidth, patchHeight := cols*glyphWidth, rows*glyphHeight
+	resized := gocv.NewMat()
+	defer resized.Close()
+	gocv.Resize(img, &resized, image.Pt(patchWidth, patchHeight), 0, 0, gocv.InterpolationArea)
+
+	gray := gocv.NewMat()
+	defer gray.Close()
+	gocv.CvtColor(resized, &gray, gocv.ColorBGRToGray)
+	grayData := gray.ToBytes()
+
+	var colorData []byte
+	if color {
+		colorData = resized.ToBytes()
+	}
+
+	frame := NewFrame(cols, rows)
+	var wg sync.WaitGroup
+	numWorkers := runtime.NumCPU()
+
+	rowJobs := make(chan int, rows)
+	for y := 0; y < rows; y++ {
+		rowJobs <- y
+	}
+	close(rowJobs)
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			patch := make([]int, glyphWidth*glyphHeight)
+			for y := range rowJobs {
+				row := frame.Row(y)
+				for x := 0; x < cols; x++ {
+					var sumR, sumG, sumB int
+					for py := 0; py < glyphHeight; py++ {
+						line := (y*glyphHeight + py) * patchWidth
+						for px := 0; px < glyphWidth; px++ {
+							offset := line + x*glyphWidth + px
+							patch[py*glyphWidth+px] = int(grayData[offset])
+							if color {
+								sumB += int(colorData[offset*3])
+								sumG += int(colorData[offset*3+1])
+								sumR += int(colorData[offset*3+2])
+							}
+						}
+					}
+
+					row[x].Rune = c.match(patch)
+					if color {
+						n := glyphWidth * glyphHeight
+						row[x].Fg = NewRGBColor(uint8(sumR/n), uint8(sumG/n), uint8(sumB/n))
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return frame, nil
+}
+
+// match는 이미지 조각과의 제곱 거리가 가장 작은 글리프를 반환합니다.
+// |p-g|² = |p|² - 2p·g + |g|² 에서 |p|²는 모든 글리프에 공통이므로 생략하고,
+// 글리프 픽셀은 0 또는 255이므로 p·g는 잉크 위치의 조각 값 합에 255를 곱한 값입니다.
+func (c *ShapeConverter) match(patch []int) rune {
+	best := c.glyphs[0].char
+	bestScore := 0
+	for i := range c.glyphs {
+		glyph := &c.glyphs[i]
+		sum := 0
+		for _, j := range glyph.ink {
+			sum += patch[j]
+		}
+		score := glyph.norm - 2*255*sum
+		if i == 0 || score < bestScore {
+			best = glyph.char
+			bestScore = score
+		}
+	}
+	return best
+}
@@ -0,0 +1,46 @@
+package media
+
+import (
+	"testing"
+
+	"gocv.io/x/gocv"
+)
+
+func TestShapeConverterMatch(t *testing.T) {
+	converter := NewShapeConverter()
+	for _, r := range []rune{' ', '#', '/', '\\', '|', '-', 'A', 'o'} {
+		patch := make([]int, glyphWidth*glyphHeight)
+		for i, v := range rasterizeGlyph(r) {
+			patch[i] = int(v)
+		}
+		if got := converter.match(patch); got != r {
+			t.Errorf("the bitmap of %q matches %q", r, got)
+		}
+	}
+}
+
+func BenchmarkShapeConverter(b *testing.B) {
+	const width, height = 1280, 720
+	data := make([]byte, width*height*3)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := (y*width + x) * 3
+			data[i] = byte(x)
+			data[i+1] = byte(y)
+			data[i+2] = byte(x ^ y)
+		}
+	}
+	img, err := gocv.NewMatFromBytes(height, width, gocv.MatTypeCV8UC3, data)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer img.Close()
+
+	converter := NewShapeConverter()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := converter.Convert(img, 200, 200, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
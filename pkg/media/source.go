@@ -0,0 +1,26 @@
+package media
+
+import (
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// FrameSource는 플레이어가 프레임을 읽고 위치를 제어하는 데 필요한 기능을 정의합니다.
+// FrameExtractor가 기본 구현입니다.
+// GetFPS, GetWidth, GetHeight, GetTotalFrames는 디코딩과 동시에 다른 고루틴에서
+// 호출되므로 열 때 읽어 둔 값을 반환해야 합니다.
+type FrameSource interface {
+	ReadNextFrame() (gocv.Mat, error)
+	GetFrameAt(d time.Duration) (gocv.Mat, error)
+	Seek(d time.Duration) error
+	GetFPS() float64
+	GetWidth() int
+	GetHeight() int
+	GetPosition() time.Duration
+	GetCurrentFrame() int
+	GetTotalFrames() int
+	Close()
+}
+
+var _ FrameSource = (*FrameExtractor)(nil)
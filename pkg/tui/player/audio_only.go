@@ -0,0 +1,167 @@
+package player
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+)
+
+const (
+	// audioOnlyControls lists the keys that matter without video
+	audioOnlyControls = "[SPACE] Pause  [<-/->] Seek  [[/]] Speed  [+/-] Volume  [M] Mute  [V] Visualizer  [Q] Quit"
+	// maxProgressWidth caps the width of the progress bar of the audio-only view
+	maxProgressWidth = 72
+	// minVisualizerRows is the least room under the audio-only view for the visualizer to be drawn
+	minVisualizerRows = 3
+)
+
+// Colors of the audio-only view
+var (
+	dimColor      = media.NewRGBColor(0x9e, 0x9e, 0x9e)
+	progressColor = media.NewRGBColor(0x3d, 0xd6, 0xf5)
+)
+
+// useAudioOnly shows the audio-only view instead of the video, which also
+// gives the visualizer strip's rows back to it
+func (p *Player) useAudioOnly() {
+	p.audioOnly = true
+	p.layout(p.screen.Size())
+	p.config.Width, p.config.Height = p.width, p.height
+}
+
+// toggleVisualizer shows or hides the visualizer of the audio-only view
+func (p *Player) toggleVisualizer() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.hideVisualizer = !p.hideVisualizer
+}
+
+// drawAudioOnly draws the title, progress and controls of the audio being
+// played, with the visualizer filling the rows under them
+func (p *Player) drawAudioOnly() *media.Frame {
+	frame := media.NewFrame(max(p.width, 0), max(p.height, 0))
+
+	p.mutex.Lock()
+	showVisualizer := !p.hideVisualizer && p.visualizer != nil
+	paused := p.isPaused
+	p.mutex.Unlock()
+
+	source := p.filename
+	if p.config.AudioFile != "" {
+		source = p.config.AudioFile
+	}
+	details := fmt.Sprintf("Audio only · %s · %.1f kHz",
+		strings.ToUpper(string(p.audioPlayer.Codec())), float64(p.audioPlayer.SampleRate())/1000)
+
+	lines := []struct {
+		text string
+		cell media.Cell
+	}{
+		{filepath.Base(source), media.Cell{Attrs: media.AttrBold}},
+		{details, p.dimCell()},
+		{},
+		{},
+		{},
+		{audioOnlyControls, p.dimCell()},
+	}
+	const progressLine = 3
+
+	// The lines sit at the top above the visualizer, or in the middle without it
+	visualizerTop := len(lines) + 2
+	top := 1
+	if !showVisualizer || frame.Height-visualizerTop < minVisualizerRows {
+		showVisualizer = false
+		top = max(0, (frame.Height-len(lines))/2)
+	}
+
+	for i, line := range lines {
+		if top+i >= frame.Height {
+			break
+		}
+		if i == progressLine {
+			p.drawProgress(frame, top+i, paused)
+			continue
+		}
+		putCentered(frame, top+i, line.text, line.cell)
+	}
+
+	if showVisualizer {
+		p.audioPlayer.Recent(p.visSamples)
+		drawing := p.visualizer.Draw(p.visSamples, frame.Width, frame.Height-visualizerTop)
+		for y := 0; y < drawing.Height; y++ {
+			copy(frame.Row(visualizerTop+y), drawing.Row(y))
+		}
+	}
+	return frame
+}
+
+// drawProgress draws the play state, position and length around a bar filled up to the position
+func (p *Player) drawProgress(frame *media.Frame, y int, paused bool) {
+	state := "▶"
+	if paused {
+		state = "❚❚"
+	}
+	length := time.Duration(float64(p.videoPlayer.GetTotalFrames()) / p.GetFPS() * float64(time.Second))
+	position := max(0, min(p.clock.Now(), length))
+	left := state + " " + utils.FormatDuration(position) + " "
+	right := " " + utils.FormatDuration(length)
+
+	width := min(frame.Width, maxProgressWidth)
+	barWidth := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if barWidth < 1 {
+		putCentered(frame, y, strings.TrimSpace(left), media.Cell{})
+		return
+	}
+	filled := 0
+	if length > 0 {
+		filled = int(float64(barWidth) * float64(position) / float64(length))
+	}
+
+	x := (frame.Width - width) / 2
+	x = putText(frame, x, y, left, media.Cell{})
+	for i := 0; i < barWidth; i++ {
+		cell := p.dimCell()
+		cell.Rune = '─'
+		if i < filled {
+			cell = media.Cell{Rune: '━'}
+			if p.color {
+				cell.Fg = progressColor
+			}
+		}
+		frame.Set(x, y, cell)
+		x++
+	}
+	putText(frame, x, y, right, media.Cell{})
+}
+
+// dimCell returns the style of secondary text
+func (p *Player) dimCell() media.Cell {
+	if !p.color {
+		return media.Cell{}
+	}
+	return media.Cell{Fg: dimColor}
+}
+
+// putCentered writes text centered on row y, cut off at the edges of the frame
+func putCentered(frame *media.Frame, y int, text string, cell media.Cell) {
+	putText(frame, max(0, (frame.Width-utf8.RuneCountInString(text))/2), y, text, cell)
+}
+
+// putText writes text from column x of row y in the style of cell and returns
+// the column after it. Text past the right edge is cut off.
+func putText(frame *media.Frame, x, y int, text string, cell media.Cell) int {
+	for _, r := range text {
+		if x >= frame.Width {
+			break
+		}
+		cell.Rune = r
+		frame.Set(x, y, cell)
+		x++
+	}
+	return x
+}
@@ -0,0 +1,256 @@
+package player
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kweonminsung/console-cinema/pkg/cast"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+)
+
+// castRefreshInterval is how often the cast player redraws the screen
+const castRefreshInterval = time.Second / 60
+
+// CastPlayer replays an asciicast v2 recording in the terminal
+type CastPlayer struct {
+	mutex sync.Mutex
+
+	filename string
+	header   cast.Header
+	events   []cast.Event
+	duration time.Duration
+
+	screen   tcell.Screen
+	terminal *cast.Terminal
+	clock    mediaClock
+	// next is the index of the first event not yet fed to the terminal
+	next int
+
+	speed    float64
+	isPaused bool
+	quit     chan struct{}
+}
+
+// NewCastPlayer loads every event of the recording at path
+func NewCastPlayer(path string) (*CastPlayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := cast.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	var events []cast.Event
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	p := &CastPlayer{
+		filename: path,
+		header:   reader.Header,
+		events:   events,
+		speed:    1,
+		quit:     make(chan struct{}),
+	}
+	if len(events) > 0 {
+		p.duration = events[len(events)-1].Time
+	}
+	return p, nil
+}
+
+// Play shows the recording until the user quits
+func (p *CastPlayer) Play() error {
+	var err error
+	p.screen, _, err = newScreen(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create screen: %v", err)
+	}
+	if err := p.screen.Init(); err != nil {
+		return fmt.Errorf("failed to initialize screen: %v", err)
+	}
+	defer p.screen.Fini()
+	p.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorReset).Foreground(tcell.ColorReset))
+
+	p.terminal = cast.NewTerminal(p.header.Width, p.header.Height)
+	p.clock.Set(0)
+
+	go p.handleEvents()
+
+	ticker := time.NewTicker(castRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.quit:
+			return nil
+		case <-ticker.C:
+			p.mutex.Lock()
+			position := p.clock.Now()
+			if position >= p.duration && !p.isPaused {
+				// Hold the last screen once the recording ends
+				p.clock.Pause()
+				p.clock.Set(p.duration)
+				p.isPaused = true
+				position = p.duration
+			}
+			p.feed(position)
+			p.draw(position)
+			p.mutex.Unlock()
+		}
+	}
+}
+
+func (p *CastPlayer) handleEvents() {
+	for {
+		ev := p.screen.PollEvent()
+		switch ev := ev.(type) {
+		case nil:
+			return
+		case *tcell.EventResize:
+			p.screen.Sync()
+		case *tcell.EventKey:
+			p.mutex.Lock()
+			switch {
+			case ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' || ev.Rune() == 'Q':
+				p.mutex.Unlock()
+				close(p.quit)
+				return
+			case ev.Rune() == ' ':
+				p.togglePause()
+			case ev.Rune() == 'r' || ev.Rune() == 'R':
+				p.seekTo(0)
+				if p.isPaused {
+					p.togglePause()
+				}
+			case ev.Key() == tcell.KeyLeft:
+				p.seekTo(p.clock.Now() - 5*time.Second)
+			case ev.Key() == tcell.KeyRight:
+				p.seekTo(p.clock.Now() + 5*time.Second)
+			case ev.Rune() == '[':
+				p.changeSpeed(-1)
+			case ev.Rune() == ']':
+				p.changeSpeed(1)
+			}
+			p.mutex.Unlock()
+		}
+	}
+}
+
+// togglePause pauses or resumes the clock. The caller must hold p.mutex.
+func (p *CastPlayer) togglePause() {
+	if p.isPaused {
+		if p.clock.Now() >= p.duration {
+			p.seekTo(0)
+		}
+		p.clock.Resume()
+	} else {
+		p.clock.Pause()
+	}
+	p.isPaused = !p.isPaused
+}
+
+// seekTo moves playback to target. Going back replays the recording from the
+// start, since terminal output can only be applied forwards.
+// The caller must hold p.mutex.
+func (p *CastPlayer) seekTo(target time.Duration) {
+	target = max(0, min(target, p.duration))
+	if target < p.clock.Now() {
+		p.terminal.Reset()
+		p.terminal.Resize(p.header.Width, p.header.Height)
+		p.next = 0
+	}
+	p.clock.Set(target)
+	p.feed(target)
+	p.screen.Clear()
+}
+
+// changeSpeed moves to the next slower (-1) or faster (1) entry of speedSteps.
+// The caller must hold p.mutex.
+func (p *CastPlayer) changeSpeed(direction int) {
+	speed := p.speed
+	if direction > 0 {
+		for _, step := range speedSteps {
+			if step > p.speed {
+				speed = step
+				break
+			}
+		}
+	} else {
+		for i := len(speedSteps) - 1; i >= 0; i-- {
+			if speedSteps[i] < p.speed {
+				speed = speedSteps[i]
+				break
+			}
+		}
+	}
+	p.speed = speed
+	p.clock.SetSpeed(speed)
+}
+
+// feed applies every event up to position to the virtual terminal.
+// The caller must hold p.mutex.
+func (p *CastPlayer) feed(position time.Duration) {
+	for ; p.next < len(p.events) && p.events[p.next].Time <= position; p.next++ {
+		event := p.events[p.next]
+		switch event.Type {
+		case cast.EventOutput:
+			p.terminal.WriteString(event.Data)
+		case cast.EventResize:
+			var width, height int
+			if _, err := fmt.Sscanf(event.Data, "%dx%d", &width, &height); err == nil {
+				p.terminal.Resize(width, height)
+				p.screen.Clear()
+			}
+		}
+	}
+}
+
+// draw shows the virtual terminal above a status line. A recording larger
+// than the screen is cropped at the right and bottom.
+// The caller must hold p.mutex.
+func (p *CastPlayer) draw(position time.Duration) {
+	width, height := p.screen.Size()
+	frame := p.terminal.Frame()
+	for y := 0; y < min(frame.Height, height-1); y++ {
+		for x, cell := range frame.Row(y)[:min(frame.Width, width)] {
+			p.screen.SetContent(x, y, cell.Rune, nil, cellStyle(cell))
+		}
+	}
+
+	status := "PLAYING"
+	if p.isPaused {
+		status = "PAUSED"
+	}
+	statusText := fmt.Sprintf("%s | %s | Time: %s/%s | Speed: %.2fx | Size: %dx%d | Controls: [SPACE] Pause/Resume | [R] Restart | [<-/->] Seek | [[/]] Speed | [Q/ESC] Quit",
+		p.filename,
+		status,
+		utils.FormatDuration(position),
+		utils.FormatDuration(p.duration),
+		p.speed,
+		frame.Width,
+		frame.Height)
+
+	style := tcell.StyleDefault.Background(tcell.ColorSilver).Foreground(tcell.ColorBlack)
+	runes := []rune(statusText)
+	for x := 0; x < width; x++ {
+		r := ' '
+		if x < len(runes) {
+			r = runes[x]
+		}
+		p.screen.SetContent(x, height-1, r, nil, style)
+	}
+	p.screen.Show()
+}
@@ -0,0 +1,71 @@
+package player
+
+import (
+	"sync"
+	"time"
+)
+
+// mediaClock tracks the playback position using the wall clock.
+// It drives playback when there is no audio to follow.
+type mediaClock struct {
+	mutex  sync.Mutex
+	base   time.Duration
+	anchor time.Time
+	paused bool
+	// speed scales how fast media time passes and is negative while playing
+	// backward; zero means normal speed
+	speed float64
+}
+
+// Set moves the clock to the given media position
+func (c *mediaClock) Set(position time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.base = position
+	c.anchor = time.Now()
+}
+
+// Now returns the current media position
+func (c *mediaClock) Now() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now()
+}
+
+func (c *mediaClock) now() time.Duration {
+	if c.paused || c.anchor.IsZero() {
+		return c.base
+	}
+	elapsed := time.Since(c.anchor)
+	if c.speed != 0 {
+		elapsed = time.Duration(float64(elapsed) * c.speed)
+	}
+	return c.base + elapsed
+}
+
+// SetSpeed changes how fast the clock runs from now on
+func (c *mediaClock) SetSpeed(speed float64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.base = c.now()
+	if !c.anchor.IsZero() {
+		c.anchor = time.Now()
+	}
+	c.speed = speed
+}
+
+// Pause stops the clock at its current position
+func (c *mediaClock) Pause() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.base = c.now()
+	c.paused = true
+}
+
+// Resume restarts the clock from where it was paused
+func (c *mediaClock) Resume() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.anchor = time.Now()
+	c.paused = false
+}
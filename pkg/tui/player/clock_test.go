@@ -0,0 +1,44 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediaClockRunsBackward(t *testing.T) {
+	var clock mediaClock
+	clock.Set(10 * time.Second)
+	clock.SetSpeed(-1)
+
+	before := clock.Now()
+	time.Sleep(20 * time.Millisecond)
+	after := clock.Now()
+	if after >= before {
+		t.Fatalf("clock did not run backward: %v then %v", before, after)
+	}
+	if before-after < 10*time.Millisecond {
+		t.Errorf("clock ran backward by %v, want about 20ms", before-after)
+	}
+}
+
+func TestMediaClockSpeed(t *testing.T) {
+	var clock mediaClock
+	clock.Set(0)
+	clock.SetSpeed(2)
+	time.Sleep(20 * time.Millisecond)
+	if now := clock.Now(); now < 40*time.Millisecond {
+		t.Errorf("clock at 2x advanced %v in 20ms, want at least 40ms", now)
+	}
+}
+
+func TestMediaClockPause(t *testing.T) {
+	var clock mediaClock
+	clock.Set(time.Second)
+	clock.SetSpeed(-1)
+	clock.Pause()
+	paused := clock.Now()
+	time.Sleep(10 * time.Millisecond)
+	if now := clock.Now(); now != paused {
+		t.Errorf("paused clock moved from %v to %v", paused, now)
+	}
+}
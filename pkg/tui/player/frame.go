@@ -0,0 +1,50 @@
+package player
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// tcellColor converts a frame color into the matching tcell color
+func tcellColor(c media.Color) tcell.Color {
+	switch {
+	case c.IsRGB():
+		r, g, b := c.RGB()
+		return tcell.NewRGBColor(int32(r), int32(g), int32(b))
+	case c.IsIndex():
+		return tcell.PaletteColor(c.Index())
+	default:
+		return tcell.ColorReset
+	}
+}
+
+// mediaColor converts a tcell color back into a frame color
+func mediaColor(c tcell.Color) media.Color {
+	switch {
+	case c.IsRGB():
+		r, g, b := c.RGB()
+		return media.NewRGBColor(uint8(r), uint8(g), uint8(b))
+	case c.Valid():
+		return media.NewIndexColor(int(c - tcell.ColorValid))
+	default:
+		return media.ColorDefault
+	}
+}
+
+// cellStyle returns the tcell style used to draw a frame cell
+func cellStyle(cell media.Cell) tcell.Style {
+	style := tcell.StyleDefault
+	if cell.Fg != media.ColorDefault {
+		style = style.Foreground(tcellColor(cell.Fg))
+	}
+	if cell.Bg != media.ColorDefault {
+		style = style.Background(tcellColor(cell.Bg))
+	}
+	if cell.Attrs&media.AttrReverse != 0 {
+		style = style.Reverse(true)
+	}
+	if cell.Attrs&media.AttrBold != 0 {
+		style = style.Bold(true)
+	}
+	return style
+}
@@ -0,0 +1,327 @@
+package player
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kweonminsung/console-cinema/pkg/cache"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+)
+
+// bookmarkJumpMargin keeps n and p from landing on the bookmark playback is already at
+const bookmarkJumpMargin = 500 * time.Millisecond
+
+// Timeline runes marking the repeat points and bookmarks
+const (
+	timelineRepeatA  = '['
+	timelineRepeatB  = ']'
+	timelineBookmark = '◆'
+)
+
+// abRepeat is the segment looped by A-B repeat
+type abRepeat struct {
+	a, b time.Duration
+	// complete is set once point B is chosen; until then only A is marked
+	complete bool
+}
+
+// start returns where the segment starts in the playback direction
+func (r *abRepeat) start(backward bool) time.Duration {
+	if backward {
+		return r.b
+	}
+	return r.a
+}
+
+// namePrompt is a bookmark name being typed on the status bar
+type namePrompt struct {
+	position time.Duration
+	text     []rune
+}
+
+// setRepeatA marks the current position as point A. A point B before it is dropped.
+func (p *Player) setRepeatA() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	position := p.masterClock()
+	if p.repeat != nil && p.repeat.complete && p.repeat.b > position {
+		p.repeat = &abRepeat{a: position, b: p.repeat.b, complete: true}
+		return
+	}
+	p.repeat = &abRepeat{a: position}
+}
+
+// setRepeatB marks the current position as point B and starts looping the
+// segment. Without point A the segment starts at the beginning of the video.
+func (p *Player) setRepeatB() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	a, b := time.Duration(0), p.masterClock()
+	if p.repeat != nil {
+		a = p.repeat.a
+	}
+	if b < a {
+		a, b = b, a
+	}
+	if b == a {
+		return
+	}
+	p.repeat = &abRepeat{a: a, b: b, complete: true}
+}
+
+// clearRepeat turns A-B repeat off
+func (p *Player) clearRepeat() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.repeat = nil
+}
+
+// loopRepeat jumps back to the start of the A-B segment once a frame at pts
+// would leave it. It returns true when it seeked.
+func (p *Player) loopRepeat(pts time.Duration) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.repeat == nil || !p.repeat.complete {
+		return false
+	}
+	backward := p.videoPlayer.IsBackward()
+	if (!backward && pts < p.repeat.b) || (backward && pts > p.repeat.a) {
+		return false
+	}
+	p.seekTo(p.repeat.start(backward))
+	return true
+}
+
+// repeatLabel describes the A-B repeat state for the status bar
+func (p *Player) repeatLabel() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	switch {
+	case p.repeat == nil:
+		return "-"
+	case !p.repeat.complete:
+		return utils.FormatDuration(p.repeat.a) + "-?"
+	default:
+		return utils.FormatDuration(p.repeat.a) + "-" + utils.FormatDuration(p.repeat.b)
+	}
+}
+
+// loadBookmarks reads the saved bookmarks of the source
+func (p *Player) loadBookmarks() {
+	bookmarks, err := cache.LoadBookmarks(p.filename)
+	if err != nil {
+		log.Printf("failed to load bookmarks: %v", err)
+		return
+	}
+	p.mutex.Lock()
+	p.bookmarks = bookmarks
+	p.mutex.Unlock()
+}
+
+// startBookmarkPrompt asks for the name of a bookmark at the current position
+func (p *Player) startBookmarkPrompt() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.prompt = &namePrompt{position: p.masterClock()}
+}
+
+// prompting reports whether a bookmark name is being typed
+func (p *Player) prompting() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.prompt != nil
+}
+
+// promptText returns the status bar line of the bookmark name prompt, or "" when there is none
+func (p *Player) promptText() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.prompt == nil {
+		return ""
+	}
+	return fmt.Sprintf("Bookmark at %s: %s_  [ENTER] Save | [ESC] Cancel", utils.FormatDuration(p.prompt.position), string(p.prompt.text))
+}
+
+// handlePromptKey edits the bookmark name being typed. Enter saves the
+// bookmark and Escape discards it.
+func (p *Player) handlePromptKey(ev *tcell.EventKey) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		name := string(p.prompt.text)
+		if name == "" {
+			name = utils.FormatDuration(p.prompt.position)
+		}
+		p.addBookmark(cache.Bookmark{Name: name, Position: p.prompt.position})
+		p.prompt = nil
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		p.prompt = nil
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(p.prompt.text) > 0 {
+			p.prompt.text = p.prompt.text[:len(p.prompt.text)-1]
+		}
+	case tcell.KeyRune:
+		p.prompt.text = append(p.prompt.text, ev.Rune())
+	}
+}
+
+// addBookmark adds a bookmark and saves the bookmarks of the source.
+// The caller must hold p.mutex.
+func (p *Player) addBookmark(bookmark cache.Bookmark) {
+	p.bookmarks = append(p.bookmarks, bookmark)
+	cache.SortBookmarks(p.bookmarks)
+	if err := cache.SaveBookmarks(p.filename, p.bookmarks); err != nil {
+		log.Printf("failed to save bookmarks: %v", err)
+	}
+}
+
+// jumpBookmark seeks to the next (1) or previous (-1) bookmark
+func (p *Player) jumpBookmark(direction int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if target, ok := bookmarkTarget(p.bookmarks, p.masterClock(), direction); ok {
+		p.seekTo(target)
+	}
+}
+
+// bookmarkTarget returns the position of the next (1) or previous (-1)
+// bookmark from position, wrapping around past the last or first one.
+// Bookmarks within bookmarkJumpMargin of position are skipped.
+func bookmarkTarget(bookmarks []cache.Bookmark, position time.Duration, direction int) (time.Duration, bool) {
+	if len(bookmarks) == 0 {
+		return 0, false
+	}
+	if direction > 0 {
+		for _, bookmark := range bookmarks {
+			if bookmark.Position > position+bookmarkJumpMargin {
+				return bookmark.Position, true
+			}
+		}
+		return bookmarks[0].Position, true
+	}
+	for i := len(bookmarks) - 1; i >= 0; i-- {
+		if bookmarks[i].Position < position-bookmarkJumpMargin {
+			return bookmarks[i].Position, true
+		}
+	}
+	return bookmarks[len(bookmarks)-1].Position, true
+}
+
+// toggleBookmarkList shows or hides the bookmark list overlay
+func (p *Player) toggleBookmarkList() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.showBookmarks = !p.showBookmarks
+}
+
+// drawBookmarkList draws the bookmark list in the top left corner of the
+// video, highlighting the bookmark playback last passed
+func (p *Player) drawBookmarkList() {
+	p.mutex.Lock()
+	show := p.showBookmarks
+	bookmarks := append([]cache.Bookmark(nil), p.bookmarks...)
+	p.mutex.Unlock()
+	if !show {
+		return
+	}
+
+	lines := []string{"Bookmarks"}
+	if len(bookmarks) == 0 {
+		lines = append(lines, "None yet, press [K] to add one")
+	}
+	position := p.videoPlayer.GetPosition()
+	current := -1
+	for i, bookmark := range bookmarks {
+		lines = append(lines, fmt.Sprintf("%2d  %s  %s", i+1, utils.FormatDuration(bookmark.Position), bookmark.Name))
+		if bookmark.Position <= position {
+			current = i
+		}
+	}
+
+	screenWidth, _ := p.screen.Size()
+	box := rect{x: 1, y: 1, height: len(lines) + 2}
+	for _, line := range lines {
+		if width := len([]rune(line)) + 4; width > box.width {
+			box.width = width
+		}
+	}
+	box.width = clamp(box.width, 0, screenWidth-2)
+	if maxHeight := p.statusTop() - 2; box.height > maxHeight {
+		box.height = maxHeight
+	}
+	if box.width < 4 || box.height < 3 {
+		return
+	}
+
+	style := tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
+	for y := box.y; y < box.y+box.height; y++ {
+		for x := box.x; x < box.x+box.width; x++ {
+			p.screen.SetContent(x, y, ' ', nil, style)
+		}
+	}
+	for x := box.x; x < box.x+box.width; x++ {
+		p.screen.SetContent(x, box.y, tcell.RuneHLine, nil, style)
+		p.screen.SetContent(x, box.y+box.height-1, tcell.RuneHLine, nil, style)
+	}
+	for y := box.y; y < box.y+box.height; y++ {
+		p.screen.SetContent(box.x, y, tcell.RuneVLine, nil, style)
+		p.screen.SetContent(box.x+box.width-1, y, tcell.RuneVLine, nil, style)
+	}
+	p.screen.SetContent(box.x, box.y, tcell.RuneULCorner, nil, style)
+	p.screen.SetContent(box.x+box.width-1, box.y, tcell.RuneURCorner, nil, style)
+	p.screen.SetContent(box.x, box.y+box.height-1, tcell.RuneLLCorner, nil, style)
+	p.screen.SetContent(box.x+box.width-1, box.y+box.height-1, tcell.RuneLRCorner, nil, style)
+
+	for i, line := range lines {
+		y := box.y + 1 + i
+		if y >= box.y+box.height-1 {
+			break
+		}
+		lineStyle := style
+		if i-1 == current {
+			lineStyle = style.Reverse(true)
+		}
+		runes := []rune(line)
+		if len(runes) > box.width-4 {
+			runes = runes[:box.width-4]
+		}
+		p.drawStyledString(box.x+2, y, string(runes), lineStyle)
+	}
+	p.bookmarkList = box
+}
+
+// drawMarkers draws the repeat points and bookmarks over the timeline bar,
+// with the repeated segment in a different color
+func (p *Player) drawMarkers(style tcell.Style) {
+	p.mutex.Lock()
+	repeat := p.repeat
+	bookmarks := p.bookmarks
+	p.mutex.Unlock()
+
+	y, _, x1 := p.timelineBar()
+	if repeat != nil && repeat.complete {
+		segmentStyle := style.Foreground(tcell.ColorMaroon)
+		for x := p.timelineColumn(repeat.a); x <= p.timelineColumn(repeat.b) && x <= x1; x++ {
+			r, _, _, _ := p.screen.GetContent(x, y)
+			p.screen.SetContent(x, y, r, nil, segmentStyle)
+		}
+	}
+	for _, bookmark := range bookmarks {
+		p.screen.SetContent(p.timelineColumn(bookmark.Position), y, timelineBookmark, nil, style)
+	}
+	if repeat != nil {
+		p.screen.SetContent(p.timelineColumn(repeat.a), y, timelineRepeatA, nil, style)
+		if repeat.complete {
+			p.screen.SetContent(p.timelineColumn(repeat.b), y, timelineRepeatB, nil, style)
+		}
+	}
+}
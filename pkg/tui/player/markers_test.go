@@ -0,0 +1,42 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/cache"
+)
+
+func TestBookmarkTarget(t *testing.T) {
+	bookmarks := []cache.Bookmark{
+		{Name: "intro", Position: 10 * time.Second},
+		{Name: "middle", Position: 30 * time.Second},
+		{Name: "end", Position: 50 * time.Second},
+	}
+	tests := []struct {
+		name      string
+		bookmarks []cache.Bookmark
+		position  time.Duration
+		direction int
+		want      time.Duration
+		wantOK    bool
+	}{
+		{"next", bookmarks, 20 * time.Second, 1, 30 * time.Second, true},
+		{"previous", bookmarks, 20 * time.Second, -1, 10 * time.Second, true},
+		{"next skips the one playing", bookmarks, 30*time.Second + 100*time.Millisecond, 1, 50 * time.Second, true},
+		{"previous skips the one playing", bookmarks, 30*time.Second + 100*time.Millisecond, -1, 10 * time.Second, true},
+		{"next wraps to the first", bookmarks, 55 * time.Second, 1, 10 * time.Second, true},
+		{"previous wraps to the last", bookmarks, 5 * time.Second, -1, 50 * time.Second, true},
+		{"next from the last wraps", bookmarks, 50 * time.Second, 1, 10 * time.Second, true},
+		{"single bookmark", bookmarks[1:2], 40 * time.Second, 1, 30 * time.Second, true},
+		{"no bookmarks", nil, 0, 1, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := bookmarkTarget(test.bookmarks, test.position, test.direction)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("bookmarkTarget() = %v, %t, want %v, %t", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package player
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// byteCounter counts the bytes written to the terminal and turns them into a rate
+type byteCounter struct {
+	total atomic.Uint64
+
+	lastTotal uint64
+	lastTime  time.Time
+}
+
+// add records n written bytes
+func (c *byteCounter) add(n int) {
+	if n > 0 {
+		c.total.Add(uint64(n))
+	}
+}
+
+// rate returns the bytes written per second since the previous call
+func (c *byteCounter) rate() float64 {
+	now := time.Now()
+	total := c.total.Load()
+	defer func() {
+		c.lastTotal, c.lastTime = total, now
+	}()
+
+	if c.lastTime.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(c.lastTime).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(total-c.lastTotal) / elapsed
+}
+
+// countingTty wraps a tcell.Tty and counts every byte written through it.
+// When record is set, the written bytes are also copied to it.
+type countingTty struct {
+	tcell.Tty
+	counter *byteCounter
+	record  io.Writer
+}
+
+// Write writes to the underlying tty and records the number of bytes written
+func (t *countingTty) Write(b []byte) (int, error) {
+	n, err := t.Tty.Write(b)
+	t.counter.add(n)
+	if t.record != nil && n > 0 {
+		t.record.Write(b[:n])
+	}
+	return n, err
+}
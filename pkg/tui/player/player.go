@@ -1,6 +1,7 @@
 package player
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -12,23 +13,19 @@ import (
 	"time"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/kweonminsung/console-cinema/pkg/ascii"
 	"github.com/kweonminsung/console-cinema/pkg/audio"
-	"github.com/kweonminsung/console-cinema/pkg/pixel"
+	"github.com/kweonminsung/console-cinema/pkg/cache"
+	"github.com/kweonminsung/console-cinema/pkg/cast"
+	"github.com/kweonminsung/console-cinema/pkg/media"
 	"github.com/kweonminsung/console-cinema/pkg/types"
 	"github.com/kweonminsung/console-cinema/pkg/utils"
+	"github.com/kweonminsung/console-cinema/pkg/video"
+	"github.com/kweonminsung/console-cinema/pkg/visualizer"
 )
 
 // getPlayerModeTitle returns the display title for the given mode
 func getPlayerModeTitle(mode string) string {
-	switch mode {
-	case "pixel":
-		return "PIXEL"
-	case "ascii":
-		return "ASCII"
-	default:
-		return "ASCII"
-	}
+	return strings.ToUpper(mode)
 }
 
 // Player represents the TUI player
@@ -36,7 +33,7 @@ type Player struct {
 	screen       tcell.Screen
 	mutex        sync.Mutex
 	fps          int
-	loop         bool
+	loopMode     video.LoopMode
 	color        bool
 	filename     string
 	mode         string
@@ -53,44 +50,97 @@ type Player struct {
 	frameCountSinceLastCheck int
 	lastFPSTime              time.Time
 	actualFPS                float64
-	currentSpeedRatio        float64
 
-	// Player instances for different modes
-	asciiPlayer *ascii.AsciiPlayer
-	pixelPlayer *pixel.PixelPlayer
+	// clock is the master clock when there is no audio and follows the audio otherwise
+	clock mediaClock
+	// seekGeneration is bumped on every seek so frames fetched before it are discarded
+	seekGeneration int
+	// avDrift is how far the last presented frame lagged behind the master clock
+	avDrift       time.Duration
+	droppedFrames int
+	// speed is the playback speed applied to the clock and the audio
+	speed float64
+	// volume is the audio volume in percent; muted silences it without changing the volume
+	volume int
+	muted  bool
+
+	// showFrame asks the paused playback loop to present one frame, for frame stepping and seeking while paused
+	showFrame bool
+	// clearPending asks the playback loop to clear the screen; only that loop touches drawn
+	clearPending bool
+
+	// Timeline hover state, updated from mouse events
+	hovering      bool
+	dragging      bool
+	hoverX        int
+	hoverPosition time.Duration
+	// thumbnails renders timeline previews; overlay is the screen area of the visible preview
+	thumbnails *thumbnailer
+	overlay    rect
+
+	// recorder captures the terminal output for --record; recordStart is when recording began
+	recorder    *cast.Recorder
+	recordStart time.Time
+	// recordScreen is set when the terminal output cannot be copied, so the
+	// presented screen is recorded as a frame instead
+	recordScreen bool
+
+	// repeat is the A-B segment being looped; nil when A-B repeat is off
+	repeat *abRepeat
+	// bookmarks are the saved bookmarks of the source, sorted by position
+	bookmarks []cache.Bookmark
+	// showBookmarks toggles the bookmark list; bookmarkList is its screen area
+	showBookmarks bool
+	bookmarkList  rect
+	// prompt is the name of a new bookmark being typed; nil otherwise
+	prompt *namePrompt
+
+	config      types.PlayerConfig
+	videoPlayer *video.VideoPlayer
 	audioPlayer *audio.AudioPlayer
+	// audioUnavailable is a short reason shown in the status bar when playing without audio
+	audioUnavailable string
+
+	// visualizer draws the playing audio in place of the video in visualizer mode
+	// or in a strip under it; nil when neither is shown
+	visualizer *visualizer.Visualizer
+	visSamples [][2]float64
+	// audioOnly shows the title, progress and controls instead of the video,
+	// for sources without a video stream; hideVisualizer hides its visualizer
+	audioOnly      bool
+	hideVisualizer bool
+
+	// quantizer maps frame colors onto the terminal palette; nil on truecolor terminals
+	quantizer  *media.Quantizer
+	colorDepth media.ColorDepth
+
+	// drawn mirrors the frame cells currently on screen; nil forces a full redraw
+	drawn *media.Frame
+	// output counts the bytes written to the terminal; nil when it cannot be measured
+	output     *byteCounter
+	outputRate float64
 }
 
 // NewPlayer creates a new TUI player
-func NewPlayer(filename string, fps int, loop bool, color bool, mode string) *Player {
+func NewPlayer(config types.PlayerConfig) *Player {
 	return &Player{
-		fps:               fps,
-		loop:              loop,
-		color:             color,
-		filename:          filename,
-		mode:              mode,
-		isPlaying:         false,
-		isPaused:          false,
-		isFinished:        false,
-		currentFrame:      0,
-		currentSpeedRatio: 1.0,
+		config:       config,
+		fps:          config.FPS,
+		color:        config.Color,
+		filename:     config.Source,
+		mode:         config.Mode,
+		isPlaying:    false,
+		isPaused:     false,
+		isFinished:   false,
+		currentFrame: 0,
 	}
 }
 
 // GetFPS returns the FPS of the video.
 func (p *Player) GetFPS() float64 {
 	var fps float64
-	switch p.mode {
-	case "pixel":
-		if p.pixelPlayer != nil {
-			fps = p.pixelPlayer.GetFPS()
-		}
-	case "ascii":
-		fallthrough
-	default:
-		if p.asciiPlayer != nil {
-			fps = p.asciiPlayer.GetFPS()
-		}
+	if p.videoPlayer != nil {
+		fps = p.videoPlayer.GetFPS()
 	}
 
 	if fps > 0 {
@@ -104,63 +154,106 @@ func (p *Player) GetFPS() float64 {
 
 // LoadFrames loads frames for playback
 func (p *Player) LoadFrames() error {
-	width, height := p.screen.Size()
-	p.width, p.height = width, height-2 // Subtract 2 for status bar
+	p.layout(p.screen.Size())
 
-	isYouTube := utils.IsValidYouTubeURL(p.filename)
+	p.config.Width, p.config.Height = p.width, p.height
+	p.config.IsYouTube = utils.IsValidYouTubeURL(p.filename)
 
-	audioPlayer, err := audio.NewAudioPlayer(p.filename, isYouTube)
+	output, err := audio.NewOutput(p.config.AudioOut)
+	if err != nil {
+		return err
+	}
+	var audioPlayer *audio.AudioPlayer
+	if p.config.AudioFile != "" {
+		audioPlayer, err = audio.NewAudioPlayer(p.config.AudioFile, false, output)
+	} else {
+		audioPlayer, err = audio.NewAudioPlayer(p.filename, p.config.IsYouTube, output)
+	}
 	if err != nil {
 		log.Printf("failed to create audio player: %v. playing without audio", err)
+		p.audioUnavailable = audioUnavailableReason(err)
+	} else {
+		log.Printf("playing audio decoded by %s", audioPlayer.Codec())
 	}
 	p.audioPlayer = audioPlayer
+	if audioPlayer != nil {
+		audioPlayer.SetSpeed(p.speed)
+		audioPlayer.SetVolume(p.volume)
+		audioPlayer.SetMuted(p.muted)
+	}
 
-	switch p.mode {
-	case "pixel":
-		pixelPlayer, err := pixel.NewPixelPlayer(p.filename, types.PlayerConfig{
-			Mode:      "pixel",
-			Color:     p.color,
-			Width:     p.width,
-			Height:    p.height,
-			FPS:       p.fps,
-			Loop:      p.loop,
-			Source:    p.filename,
-			IsYouTube: isYouTube,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create pixel player: %v", err)
+	var videoPlayer *video.VideoPlayer
+	switch {
+	case p.mode == visualizer.ModeName:
+		if audioPlayer == nil {
+			return fmt.Errorf("%s mode needs audio (%s)", p.mode, p.audioUnavailable)
 		}
-		p.pixelPlayer = pixelPlayer
-		p.videoWidth = pixelPlayer.GetVideoWidth()
-		p.videoHeight = pixelPlayer.GetVideoHeight()
-		return nil
-	case "ascii":
-		fallthrough
+		videoPlayer, err = p.newBlankVideoPlayer()
+	case audioPlayer != nil && p.config.AudioFile == "" && audioPlayer.Codec() != audio.CodecFFmpeg:
+		// A source decoded without ffmpeg is an audio file; any picture in it is cover art
+		log.Printf("%s is an audio file, playing audio only", p.filename)
+		p.useAudioOnly()
+		videoPlayer, err = p.newBlankVideoPlayer()
 	default:
-		asciiPlayer, err := ascii.NewAsciiPlayer(p.filename, types.PlayerConfig{
-			Mode:      "ascii",
-			Color:     p.color,
-			Width:     p.width,
-			Height:    p.height,
-			FPS:       p.fps,
-			Loop:      p.loop,
-			Source:    p.filename,
-			IsYouTube: isYouTube,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create ASCII player: %v", err)
+		videoPlayer, err = video.NewVideoPlayer(p.filename, p.config)
+		// Only a source without any video stream falls back to audio only;
+		// any other failure to open the video is reported as it is
+		if err != nil && audioPlayer != nil && !p.config.IsYouTube {
+			if hasVideo, probeErr := audio.ProbeVideoStream(p.filename); probeErr == nil && !hasVideo {
+				log.Printf("%s has no video stream, playing audio only", p.filename)
+				p.useAudioOnly()
+				videoPlayer, err = p.newBlankVideoPlayer()
+			}
 		}
-		p.asciiPlayer = asciiPlayer
-		p.videoWidth = asciiPlayer.GetVideoWidth()
-		p.videoHeight = asciiPlayer.GetVideoHeight()
-		return nil
 	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s player: %v", p.mode, err)
+	}
+	if p.mode == visualizer.ModeName || p.audioOnly || p.stripHeight() > 0 {
+		p.setupVisualizer()
+	}
+	if p.videoPlayer != nil {
+		p.videoPlayer.Close()
+	}
+	p.videoPlayer = videoPlayer
+	p.videoWidth = videoPlayer.GetVideoWidth()
+	p.videoHeight = videoPlayer.GetVideoHeight()
+	return nil
 }
 
 // Play starts the TUI player
 func (p *Player) Play() error {
-	var err error
-	p.screen, err = tcell.NewScreen()
+	loopMode, err := video.ParseLoopMode(p.config.Loop)
+	if err != nil {
+		return err
+	}
+	p.loopMode = loopMode
+
+	p.speed = p.config.Speed
+	if p.speed == 0 {
+		p.speed = 1
+	}
+	if p.speed < minSpeed || p.speed > maxSpeed {
+		return fmt.Errorf("speed must be between %g and %g, got %g", minSpeed, maxSpeed, p.speed)
+	}
+	p.volume = p.config.Volume
+	if p.volume < 0 || p.volume > audio.MaxVolume {
+		return fmt.Errorf("volume must be between 0 and %d, got %d", audio.MaxVolume, p.volume)
+	}
+	if _, err := visualizer.ParseStyle(p.config.Visualizer); err != nil {
+		return err
+	}
+	if p.config.VisualizerStrip < 0 {
+		return fmt.Errorf("visualizer strip must not be negative, got %d", p.config.VisualizerStrip)
+	}
+
+	record, err := p.openRecorder()
+	if err != nil {
+		return err
+	}
+	defer p.closeRecorder()
+
+	p.screen, p.output, err = newScreen(record)
 	if err != nil {
 		return fmt.Errorf("failed to create screen: %v", err)
 	}
@@ -168,16 +261,28 @@ func (p *Player) Play() error {
 		return fmt.Errorf("failed to initialize screen: %v", err)
 	}
 	defer p.screen.Fini()
-	if p.audioPlayer != nil {
-		defer p.audioPlayer.Close()
+	p.recordScreen = p.recorder != nil && p.output == nil
+	p.startRecording()
+	p.screen.EnableMouse()
+
+	if err := p.setupColors(); err != nil {
+		return err
 	}
 
 	if err := p.LoadFrames(); err != nil {
 		return fmt.Errorf("failed to load frames: %v", err)
 	}
+	if p.audioPlayer != nil {
+		// Stops the ffmpeg process decoding the audio
+		defer p.audioPlayer.Close()
+	}
+
+	p.thumbnails = newThumbnailer(p.config)
+	defer p.thumbnails.Close()
+	p.loadBookmarks()
 
 	p.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorReset).Foreground(tcell.ColorReset))
-	p.screen.Clear()
+	p.clearScreen()
 
 	// Handle interrupt signals
 	go p.handleInterrupt()
@@ -189,15 +294,63 @@ func (p *Player) Play() error {
 	return nil
 }
 
+// audioUnavailableReason shortens why the audio player could not be created for the status bar
+func audioUnavailableReason(err error) string {
+	switch {
+	case errors.Is(err, audio.ErrFFmpegNotFound):
+		return "no ffmpeg"
+	case errors.Is(err, audio.ErrNoAudioTrack):
+		return "no audio track"
+	default:
+		return "audio failed"
+	}
+}
+
+// setupColors resolves the color depth, detecting it from the terminal when
+// set to auto, and prepares the palette quantizer for non-truecolor terminals
+func (p *Player) setupColors() error {
+	depth, err := media.ParseColorDepth(p.config.ColorDepth)
+	if err != nil {
+		return err
+	}
+	if depth == media.ColorDepthAuto {
+		depth = detectColorDepth(p.screen)
+	}
+
+	p.colorDepth = depth
+	p.quantizer = nil
+	if depth != media.ColorDepthTrueColor {
+		p.quantizer = media.NewQuantizer(depth, p.config.ColorDither)
+	}
+	return nil
+}
+
+// detectColorDepth determines the color depth from COLORTERM and the terminfo capabilities reported by tcell
+func detectColorDepth(screen tcell.Screen) media.ColorDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return media.ColorDepthTrueColor
+	}
+	return media.ColorDepthFromCount(screen.Colors())
+}
+
 func (p *Player) handleInterrupt() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
+	p.exit()
+}
+
+// exit restores the terminal, finishes the recording and the audio output and ends the process
+func (p *Player) exit() {
 	p.isPlaying = false
 	if p.audioPlayer != nil {
-		p.audioPlayer.Close()
+		if err := p.audioPlayer.Close(); err != nil {
+			log.Printf("failed to close audio: %v", err)
+		}
 	}
 	p.screen.Fini()
+	p.closeRecorder()
 	os.Exit(0)
 }
 
@@ -208,41 +361,40 @@ func (p *Player) handleEvents() {
 		case *tcell.EventResize:
 			p.screen.Sync()
 			width, height := p.screen.Size()
-			p.width, p.height = width, height-2 // Subtract 2 for status bar
+			p.layout(width, height)
+			p.recordResize(width, height)
 
-			switch p.mode {
-			case "pixel":
-				if p.pixelPlayer != nil {
-					p.pixelPlayer.UpdateSize(p.width, p.height)
-				}
-			case "ascii":
-				fallthrough
-			default:
-				if p.asciiPlayer != nil {
-					p.asciiPlayer.UpdateSize(p.width, p.height)
-				}
+			if p.videoPlayer != nil {
+				p.videoPlayer.UpdateSize(p.width, p.height)
+			}
+			p.requestClear()
+		case *tcell.EventMouse:
+			if !p.isFinished {
+				p.handleMouse(ev)
 			}
-			p.screen.Clear()
 		case *tcell.EventKey:
 			if p.isFinished {
 				if ev.Rune() == 'r' || ev.Rune() == 'R' {
 					p.rewind()
 					p.isFinished = false
 					p.isPlaying = true
-					p.screen.Clear()
+					p.requestClear()
 				} else if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' || ev.Rune() == 'Q' {
-					p.isPlaying = false
-					p.screen.Fini()
-					os.Exit(0)
+					p.exit()
 				}
+			} else if p.prompting() {
+				p.handlePromptKey(ev)
 			} else {
 				if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' || ev.Rune() == 'Q' {
-					p.isPlaying = false
-					p.screen.Fini()
-					os.Exit(0)
+					p.exit()
 				} else if ev.Rune() == ' ' {
 					p.isPaused = !p.isPaused
-					if p.audioPlayer != nil {
+					if p.isPaused {
+						p.clock.Pause()
+					} else {
+						p.clock.Resume()
+					}
+					if p.audioActive() {
 						if p.isPaused {
 							p.audioPlayer.Pause()
 						} else {
@@ -255,35 +407,188 @@ func (p *Player) handleEvents() {
 					p.seek(5 * time.Second)
 				} else if ev.Key() == tcell.KeyLeft {
 					p.seek(-5 * time.Second)
+				} else if ev.Rune() == '.' {
+					p.stepFrame(1)
+				} else if ev.Rune() == ',' {
+					p.stepFrame(-1)
+				} else if ev.Rune() == '[' {
+					p.changeSpeed(-1)
+				} else if ev.Rune() == ']' {
+					p.changeSpeed(1)
+				} else if ev.Rune() >= '0' && ev.Rune() <= '9' {
+					p.seekToPercent(int(ev.Rune() - '0'))
+				} else if ev.Rune() == 'a' || ev.Rune() == 'A' {
+					p.setRepeatA()
+				} else if ev.Rune() == 'b' || ev.Rune() == 'B' {
+					p.setRepeatB()
+				} else if ev.Rune() == 'x' || ev.Rune() == 'X' {
+					p.clearRepeat()
+				} else if ev.Rune() == '+' || ev.Rune() == '=' {
+					p.changeVolume(volumeStep)
+				} else if ev.Rune() == '-' {
+					p.changeVolume(-volumeStep)
+				} else if ev.Rune() == 'm' || ev.Rune() == 'M' {
+					p.toggleMute()
+				} else if ev.Rune() == 'k' || ev.Rune() == 'K' {
+					p.startBookmarkPrompt()
+				} else if ev.Rune() == 'n' || ev.Rune() == 'N' {
+					p.jumpBookmark(1)
+				} else if ev.Rune() == 'p' || ev.Rune() == 'P' {
+					p.jumpBookmark(-1)
+				} else if ev.Rune() == 'l' || ev.Rune() == 'L' {
+					p.toggleBookmarkList()
+				} else if ev.Rune() == 'v' || ev.Rune() == 'V' {
+					p.toggleVisualizer()
 				}
 			}
 		}
 	}
 }
 
+// Playback speeds selectable with the [ and ] keys
+var speedSteps = []float64{0.25, 0.5, 0.75, 1, 1.25, 1.5, 2, 3, 4}
+
+const (
+	minSpeed = 0.25
+	maxSpeed = 4.0
+)
+
+// volumeStep is how much the + and - keys change the volume, in percent
+const volumeStep = 10
+
+// maxSyncWait bounds a single wait for an early frame so pauses and seeks are noticed promptly
+const maxSyncWait = 50 * time.Millisecond
+
 func (p *Player) seek(duration time.Duration) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	switch p.mode {
-	case "pixel":
-		if p.pixelPlayer != nil {
-			p.pixelPlayer.Seek(duration)
+	p.seekTo(p.masterClock() + duration)
+}
+
+// seekToPercent jumps to the given tenth of the video, so 0 is the start and 9 is 90%
+func (p *Player) seekToPercent(tenths int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.videoPlayer == nil {
+		return
+	}
+	p.seekTo(p.videoPlayer.GetDuration() * time.Duration(tenths) / 10)
+}
+
+// stepFrame pauses playback and shows the next (1) or previous (-1) frame
+func (p *Player) stepFrame(direction int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.videoPlayer == nil {
+		return
+	}
+	if !p.isPaused {
+		p.isPaused = true
+		p.clock.Pause()
+		if p.audioPlayer != nil {
+			p.audioPlayer.Pause()
 		}
-	case "ascii":
-		fallthrough
-	default:
-		if p.asciiPlayer != nil {
-			p.asciiPlayer.Seek(duration)
+	}
+
+	// The video player returns the following frame in its own direction;
+	// stepping the other way seeks past the current frame first
+	frameInterval := time.Duration(float64(time.Second) / p.GetFPS())
+	backward := p.videoPlayer.IsBackward()
+	if direction < 0 && !backward {
+		p.seekTo(p.videoPlayer.GetPosition() - frameInterval)
+	} else if direction > 0 && backward {
+		p.seekTo(p.videoPlayer.GetPosition() + 2*frameInterval)
+	}
+	p.showFrame = true
+}
+
+// changeVolume raises or lowers the volume by delta percent and unmutes
+func (p *Player) changeVolume(delta int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.volume = max(0, min(p.volume+delta, audio.MaxVolume))
+	p.muted = false
+	if p.audioPlayer != nil {
+		p.audioPlayer.SetVolume(p.volume)
+		p.audioPlayer.SetMuted(false)
+	}
+}
+
+// toggleMute silences or restores the audio
+func (p *Player) toggleMute() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.muted = !p.muted
+	if p.audioPlayer != nil {
+		p.audioPlayer.SetMuted(p.muted)
+	}
+}
+
+// volumeLabel describes the volume for the status bar
+func (p *Player) volumeLabel() string {
+	if p.muted {
+		return "muted"
+	}
+	return fmt.Sprintf("%d%%", p.volume)
+}
+
+// changeSpeed moves to the next slower (-1) or faster (1) entry of speedSteps
+func (p *Player) changeSpeed(direction int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	speed := p.speed
+	if direction > 0 {
+		for _, step := range speedSteps {
+			if step > p.speed {
+				speed = step
+				break
+			}
+		}
+	} else {
+		for i := len(speedSteps) - 1; i >= 0; i-- {
+			if speedSteps[i] < p.speed {
+				speed = speedSteps[i]
+				break
+			}
 		}
 	}
+
+	p.speed = speed
+	p.clock.SetSpeed(p.clockSpeed())
 	if p.audioPlayer != nil {
-		if err := p.audioPlayer.Seek(duration); err != nil {
+		p.audioPlayer.SetSpeed(speed)
+	}
+}
+
+// seekTo moves video, audio and the clock to the target position.
+// The caller must hold p.mutex.
+func (p *Player) seekTo(target time.Duration) {
+	if target < 0 {
+		target = 0
+	}
+
+	if p.videoPlayer != nil {
+		p.videoPlayer.SeekTo(target)
+	}
+	if p.audioPlayer != nil {
+		if err := p.audioPlayer.SeekTo(target); err != nil {
 			log.Printf("failed to seek audio: %v", err)
 		}
 	}
+	p.clock.Set(target)
+	p.seekGeneration++
+	if p.isPaused {
+		p.showFrame = true
+	}
 }
 
+// rewind restarts playback from the beginning, or from the end in reverse loop mode
 func (p *Player) rewind() {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -291,160 +596,335 @@ func (p *Player) rewind() {
 	p.startTime = time.Now()
 	p.currentFrame = 0
 	p.isPaused = false
+	p.droppedFrames = 0
+	p.clock.Resume()
+	p.restart()
+	if p.audioActive() {
+		p.audioPlayer.Resume()
+	}
+}
 
-	switch p.mode {
-	case "pixel":
-		if p.pixelPlayer != nil {
-			p.pixelPlayer.Seek(-999 * time.Hour) // Seek to beginning
+// restart moves to the start of the video, or to its end when the loop mode plays backward.
+// The caller must hold p.mutex.
+func (p *Player) restart() {
+	if p.loopMode == video.LoopReverse {
+		p.setBackward(true)
+		p.seekTo(p.videoPlayer.GetDuration())
+		return
+	}
+	p.setBackward(false)
+	p.seekTo(0)
+}
+
+// handleEndOfStream applies the loop mode when no more frames come in the
+// current direction. It returns false when playback should finish.
+func (p *Player) handleEndOfStream() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	backward := p.videoPlayer.IsBackward()
+	if p.repeat != nil && p.repeat.complete {
+		p.seekTo(p.repeat.start(backward))
+		return true
+	}
+	switch p.loopMode {
+	case video.LoopRestart, video.LoopReverse:
+		p.restart()
+	case video.LoopPingPong:
+		if backward {
+			p.setBackward(false)
+			p.seekTo(0)
+		} else {
+			// Turn around at the last frame; the open extractor and audio are reused
+			p.setBackward(true)
+			p.clock.Set(p.videoPlayer.GetPosition())
+			p.seekGeneration++
 		}
-	case "ascii":
-		fallthrough
 	default:
-		if p.asciiPlayer != nil {
-			p.asciiPlayer.Seek(-999 * time.Hour) // Seek to beginning
-		}
-	}
-	if p.audioPlayer != nil {
-		p.audioPlayer.Rewind()
-		go p.audioPlayer.Play()
+		return false
 	}
+	return true
 }
 
-func (p *Player) playbackLoop() {
-	var getNextFrame func() (string, error)
-	var getFPS func() float64
-	var getCurrentFrame func() int
-	var getTotalFrames func() int
-
-	switch p.mode {
-	case "pixel":
-		getNextFrame = p.pixelPlayer.GetNextFrame
-		getFPS = p.pixelPlayer.GetFPS
-		getCurrentFrame = p.pixelPlayer.GetCurrentFrame
-		getTotalFrames = p.pixelPlayer.GetTotalFrames
-	default:
-		getNextFrame = p.asciiPlayer.GetNextFrame
-		getFPS = p.asciiPlayer.GetFPS
-		getCurrentFrame = p.asciiPlayer.GetCurrentFrame
-		getTotalFrames = p.asciiPlayer.GetTotalFrames
+// setBackward changes the playback direction. Audio is paused while playing
+// backward and the clock runs in the same direction as the frames.
+// The caller must hold p.mutex.
+func (p *Player) setBackward(backward bool) {
+	if p.videoPlayer.IsBackward() == backward {
+		return
 	}
+	p.videoPlayer.SetBackward(backward)
+	p.clock.SetSpeed(p.clockSpeed())
 
-	fps := p.fps
-	if fps <= 0 {
-		fps = int(getFPS())
-		if fps <= 0 {
-			fps = 30
+	if p.audioPlayer != nil {
+		if backward || p.isPaused {
+			p.audioPlayer.Pause()
+		} else {
+			p.audioPlayer.Resume()
 		}
 	}
+}
 
-	ticker := time.NewTicker(time.Second / time.Duration(fps))
-	defer ticker.Stop()
+// clockSpeed returns the rate of the media clock, negative while playing backward
+func (p *Player) clockSpeed() float64 {
+	if p.videoPlayer != nil && p.videoPlayer.IsBackward() {
+		return -p.speed
+	}
+	return p.speed
+}
 
+// audioActive reports whether audio is following playback; it is silent while playing backward
+func (p *Player) audioActive() bool {
+	return p.audioPlayer != nil && !p.videoPlayer.IsBackward()
+}
+
+// masterClock returns the playback position frames are timed against.
+// The audio position is used while audio is playing, the wall clock otherwise.
+func (p *Player) masterClock() time.Duration {
+	if p.audioActive() && !p.audioPlayer.Finished() {
+		position := p.audioPlayer.Position()
+		p.clock.Set(position)
+		return position
+	}
+	return p.clock.Now()
+}
+
+// playbackLoop presents each frame when the master clock reaches its
+// presentation timestamp. Frames that are already late are dropped, early
+// frames are held back, and --fps caps how many frames are shown per second.
+func (p *Player) playbackLoop() {
 	p.isPlaying = true
 	p.startTime = time.Now()
 	p.lastFPSTime = time.Now()
+	p.clock.SetSpeed(p.speed)
+	p.clock.Set(0)
 
 	if p.audioPlayer != nil {
-		go p.audioPlayer.Play()
+		go func() {
+			if err := p.audioPlayer.Play(); err != nil {
+				log.Printf("failed to play audio: %v", err)
+			}
+		}()
 	}
+	if p.loopMode == video.LoopReverse {
+		p.mutex.Lock()
+		p.restart()
+		p.mutex.Unlock()
+	}
+
+	var (
+		pending      *media.Frame
+		pendingPTS   time.Duration
+		lastPTS      time.Duration
+		hasPresented bool
+		generation   int
+	)
 
-	for range ticker.C {
+	for {
 		if !p.isPlaying {
 			if p.isFinished {
+				time.Sleep(maxSyncWait)
 				continue
 			}
 			return
 		}
-		if !p.isPaused {
-			p.mutex.Lock()
-			frame, err := getNextFrame()
-			p.mutex.Unlock()
+		p.mutex.Lock()
+		// While paused, a frame is only presented when stepping or seeking
+		stepping := p.isPaused && p.showFrame
+		p.showFrame = false
+		paused := p.isPaused && !stepping
+		clearing := p.clearPending
+		p.clearPending = false
+		p.mutex.Unlock()
+		if clearing {
+			p.clearScreen()
+		}
+		if paused {
+			if p.audioOnly {
+				// The audio-only view holds no frame, so it is redrawn to follow seeks and key presses
+				p.drawFrame(nil)
+			}
+			p.refreshStatus()
+			time.Sleep(maxSyncWait)
+			continue
+		}
+
+		p.mutex.Lock()
+		if generation != p.seekGeneration {
+			generation = p.seekGeneration
+			pending, hasPresented = nil, false
+		}
+		p.mutex.Unlock()
+
+		if pending == nil {
+			// Decoding and conversion run ahead in the video player's pipeline,
+			// so this only waits when the pipeline falls behind
+			frame, err := p.videoPlayer.GetNextFrame()
 			if err != nil {
-				if p.loop {
-					// Reset by reloading frames
-					if loadErr := p.LoadFrames(); loadErr != nil {
-						log.Printf("failed to reload frames for looping: %v", loadErr)
-						p.isPlaying = false
-						return
-					}
-					p.startTime = time.Now()
-					p.currentFrame = 0
-					if p.audioPlayer != nil {
-						p.audioPlayer.Rewind()
-					}
-					continue
-				} else {
+				if !p.handleEndOfStream() {
 					p.isPlaying = false
 					p.isFinished = true
 					p.displayEndMessage()
-					continue
 				}
+				continue
+			}
+
+			p.mutex.Lock()
+			stale := generation != p.seekGeneration
+			p.mutex.Unlock()
+			if stale {
+				continue
 			}
+			pending, pendingPTS = frame, p.videoPlayer.GetPosition()
+		}
 
-			p.drawFrame(frame)
-			p.currentFrame++
-			p.frameCountSinceLastCheck++
+		// Stepping may leave the A-B segment; playback stays inside it
+		if !stepping && p.loopRepeat(pendingPTS) {
+			pending = nil
+			continue
 		}
 
-		if time.Since(p.lastFPSTime) >= (time.Second / 10) { // Update 10 times per second
-			p.actualFPS = float64(p.frameCountSinceLastCheck) / time.Since(p.lastFPSTime).Seconds()
-			p.lastFPSTime = time.Now()
-			p.frameCountSinceLastCheck = 0
-
-			if p.audioPlayer != nil {
-				targetFPS := p.GetFPS()
-				if targetFPS > 0 && p.actualFPS > 0 {
-					targetRatio := p.actualFPS / targetFPS
-					// Smooth the ratio change
-					p.currentSpeedRatio = p.currentSpeedRatio*0.7 + targetRatio*0.3
-					if p.currentSpeedRatio < 0.1 {
-						p.currentSpeedRatio = 0.1
-					}
-					p.audioPlayer.SetSpeed(p.currentSpeedRatio)
-				}
+		if stepping {
+			p.presentStep(pending, pendingPTS)
+			lastPTS, hasPresented = pendingPTS, true
+			pending = nil
+			continue
+		}
+
+		frameInterval := time.Duration(float64(time.Second) / p.GetFPS())
+		now := p.masterClock()
+
+		// Timestamps decrease while playing backward, so measure along the playback direction
+		direction := time.Duration(1)
+		if p.videoPlayer.IsBackward() {
+			direction = -1
+		}
+		lateness := (now - pendingPTS) * direction
+
+		// The next frame is already due, so showing this one would only add lag
+		if lateness > frameInterval {
+			p.droppedFrames++
+			pending = nil
+			continue
+		}
+		if wait := time.Duration(float64(-lateness) / p.speed); wait > 0 {
+			if wait > maxSyncWait {
+				wait = maxSyncWait
 			}
+			time.Sleep(wait)
+			continue
+		}
+		// Skip frames that come sooner than the --fps display rate allows; at higher
+		// speeds the same display rate covers more media time
+		advance := (pendingPTS - lastPTS) * direction
+		if p.fps > 0 && hasPresented && advance >= 0 &&
+			advance < time.Duration(float64(time.Second)*p.speed/float64(p.fps))-frameInterval/2 {
+			pending = nil
+			continue
 		}
 
-		p.drawStatus(getCurrentFrame, getTotalFrames)
-		p.screen.Show()
+		p.avDrift = lateness
+		p.drawFrame(pending)
+		lastPTS, hasPresented = pendingPTS, true
+		pending = nil
+		p.currentFrame++
+		p.frameCountSinceLastCheck++
+		p.refreshStatus()
 	}
 }
 
-func (p *Player) drawFrame(frame string) {
-	p.screen.Clear()
-	lines := strings.Split(frame, "\n")
-	for y, line := range lines {
-		p.drawString(0, y, line)
+// presentStep draws a frame shown while paused and moves the clocks to it,
+// so playback resumes from the stepped frame
+func (p *Player) presentStep(frame *media.Frame, pts time.Duration) {
+	p.mutex.Lock()
+	p.clock.Set(pts)
+	if p.audioPlayer != nil {
+		if err := p.audioPlayer.SeekTo(pts); err != nil {
+			log.Printf("failed to seek audio: %v", err)
+		}
 	}
+	p.mutex.Unlock()
+
+	p.avDrift = 0
+	p.drawFrame(frame)
+	p.refreshStatus()
 }
 
-func (p *Player) drawString(x, y int, str string) {
-	style := tcell.StyleDefault
-	runes := []rune(str)
-	i := 0
-	for i < len(runes) {
-		r := runes[i]
-		if r == '[' && i+8 < len(runes) && runes[i+1] == '#' && runes[i+8] == ']' {
-			hex := string(runes[i+2 : i+8])
-			if rgb, err := strconv.ParseInt(hex, 16, 32); err == nil {
-				color := tcell.NewHexColor(int32(rgb))
-				style = style.Foreground(color)
+// refreshStatus updates the playback statistics and redraws the status bar
+func (p *Player) refreshStatus() {
+	if time.Since(p.lastFPSTime) >= (time.Second / 10) { // Update 10 times per second
+		p.actualFPS = float64(p.frameCountSinceLastCheck) / time.Since(p.lastFPSTime).Seconds()
+		p.lastFPSTime = time.Now()
+		p.frameCountSinceLastCheck = 0
+		if p.output != nil {
+			p.outputRate = p.output.rate()
+		}
+	}
+
+	p.drawStatus()
+	p.drawOverlays()
+	p.show()
+}
+
+// requestClear has the playback loop clear the screen before it draws again.
+// Event handlers use it instead of clearScreen, which the playback loop may be running at the same time.
+func (p *Player) requestClear() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.clearPending = true
+}
+
+// clearScreen clears the screen and forgets the drawn frame so the next one is fully redrawn.
+// Only the playback loop calls it once playback has started.
+func (p *Player) clearScreen() {
+	p.screen.Clear()
+	p.drawn = nil
+}
+
+// drawFrame draws only the cells that differ from the frame already on screen.
+// Cells whose colors moved by no more than the color tolerance are left as they are.
+func (p *Player) drawFrame(frame *media.Frame) {
+	frame = p.visualize(frame)
+	if p.quantizer != nil {
+		frame = p.quantizer.QuantizeFrame(frame)
+	}
+
+	if p.drawn == nil || p.drawn.Width != frame.Width || p.drawn.Height != frame.Height {
+		p.clearScreen()
+		for y := 0; y < frame.Height; y++ {
+			for x, cell := range frame.Row(y) {
+				p.screen.SetContent(x, y, cell.Rune, nil, cellStyle(cell))
 			}
-			i += 9 // Move index past the color tag
-			continue
 		}
+		p.drawn = frame.Clone()
+		return
+	}
+
+	for y := 0; y < frame.Height; y++ {
+		drawnRow := p.drawn.Row(y)
+		for x, cell := range frame.Row(y) {
+			if cell.Similar(drawnRow[x], p.config.ColorTolerance) {
+				continue
+			}
+			p.screen.SetContent(x, y, cell.Rune, nil, cellStyle(cell))
+			drawnRow[x] = cell
+		}
+	}
+}
+
+func (p *Player) drawString(x, y int, str string) {
+	p.drawStyledString(x, y, str, tcell.StyleDefault)
+}
+
+func (p *Player) drawStyledString(x, y int, str string, style tcell.Style) {
+	for _, r := range str {
 		p.screen.SetContent(x, y, r, nil, style)
 		x++
-		i++
 	}
 }
 
-func (p *Player) drawStatus(getCurrentFrame func() int, getTotalFrames func() int) {
-	_, screenHeight := p.screen.Size()
-	statusY1 := p.height
-	if statusY1 >= screenHeight-1 {
-		statusY1 = screenHeight - 2
-	}
+func (p *Player) drawStatus() {
+	statusY1 := p.statusTop() + 1 // The timeline takes the first row
 	statusY2 := statusY1 + 1
 
 	status := "PLAYING"
@@ -456,16 +936,37 @@ func (p *Player) drawStatus(getCurrentFrame func() int, getTotalFrames func() in
 	}
 
 	mode := "Normal"
-	if p.loop {
-		mode = "Loop"
+	if p.loopMode != video.LoopOff {
+		mode = "Loop " + string(p.loopMode)
+	}
+	if p.videoPlayer.IsBackward() {
+		mode += " (reverse)"
 	}
 
-	currentFrame := getCurrentFrame()
-	totalFrames := getTotalFrames()
-	currentTime := time.Duration(float64(currentFrame)/p.GetFPS()) * time.Second
+	playerTitle := getPlayerModeTitle(p.mode)
+	if p.audioOnly {
+		playerTitle = "AUDIO ONLY"
+	}
+
+	currentFrame := p.videoPlayer.GetCurrentFrame()
+	totalFrames := p.videoPlayer.GetTotalFrames()
+	currentTime := p.videoPlayer.GetPosition()
 	totalTime := time.Duration(float64(totalFrames)/p.GetFPS()) * time.Second
 
-	statusText1 := fmt.Sprintf("Mode: %s | FPS: %.1f/%d | Status: %s | Frame: %d/%d | Time: %s/%s | Resolution: %s | Player: %s",
+	drift := "-"
+	if p.audioUnavailable != "" {
+		drift = p.audioUnavailable
+	}
+	if p.audioPlayer != nil {
+		drift = fmt.Sprintf("%+dms", p.avDrift.Milliseconds())
+	}
+
+	output := "-"
+	if p.output != nil {
+		output = utils.FormatByteRate(p.outputRate)
+	}
+
+	statusText1 := fmt.Sprintf("Mode: %s | FPS: %.1f/%d | Status: %s | Frame: %d/%d | Time: %s/%s | Resolution: %s | Player: %s | Speed: %.2fx | Volume: %s | A-B: %s | Colors: %s | A/V: %s | Dropped: %d | Output: %s",
 		mode,
 		p.actualFPS,
 		p.fps,
@@ -475,9 +976,19 @@ func (p *Player) drawStatus(getCurrentFrame func() int, getTotalFrames func() in
 		utils.FormatDuration(currentTime),
 		utils.FormatDuration(totalTime),
 		strconv.Itoa(p.width)+"x"+strconv.Itoa(p.height),
-		getPlayerModeTitle(p.mode))
+		playerTitle,
+		p.speed,
+		p.volumeLabel(),
+		p.repeatLabel(),
+		p.colorDepth,
+		drift,
+		p.droppedFrames,
+		output)
 
-	statusText2 := "Controls: [SPACE] Pause/Resume | [R] Restart | [<-/->] Seek | [Q/ESC] Quit"
+	statusText2 := "Controls: [SPACE] Pause/Resume | [R] Restart | [<-/->] Seek | [,/.] Step | [0-9] Jump | [[/]] Speed | [+/-] Volume | [M] Mute | [A/B/X] Repeat | [K] Bookmark | [N/P] Next/Prev | [L] List | [Mouse] Scrub timeline | [Q/ESC] Quit"
+	if prompt := p.promptText(); prompt != "" {
+		statusText2 = prompt
+	}
 
 	// Clear status lines
 	width, _ := p.screen.Size()
@@ -498,10 +1009,12 @@ func (p *Player) drawStatus(getCurrentFrame func() int, getTotalFrames func() in
 	for i := 0; i < len(runes2) && i < width; i++ {
 		p.screen.SetContent(i, statusY2, runes2[i], nil, style)
 	}
+
+	p.drawTimeline(style)
 }
 
 func (p *Player) displayEndMessage() {
-	p.screen.Clear()
+	p.clearScreen()
 	screenWidth, screenHeight := p.screen.Size()
 
 	boxWidth := 50
@@ -541,5 +1054,5 @@ func (p *Player) displayEndMessage() {
 	p.drawString(x+(boxWidth-len(msg2))/2, y+3, msg2)
 	p.drawString(x+(boxWidth-len(msg3))/2, y+5, msg3)
 
-	p.screen.Show()
+	p.show()
 }
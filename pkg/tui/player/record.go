@@ -0,0 +1,97 @@
+package player
+
+import (
+	"io"
+	"log"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kweonminsung/console-cinema/pkg/cast"
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// openRecorder creates the asciicast file for --record. It returns the
+// writer the terminal output is copied to, or nil when not recording.
+func (p *Player) openRecorder() (io.Writer, error) {
+	if p.config.Record == "" {
+		return nil, nil
+	}
+	recorder, err := cast.Create(p.config.Record, p.filename)
+	if err != nil {
+		return nil, err
+	}
+	p.recorder = recorder
+	return recorder, nil
+}
+
+// startRecording writes the recording header once the screen size is known
+func (p *Player) startRecording() {
+	if p.recorder == nil {
+		return
+	}
+	width, height := p.screen.Size()
+	if err := p.recorder.Start(width, height); err != nil {
+		log.Printf("failed to start recording: %v", err)
+	}
+	p.recordStart = time.Now()
+}
+
+// show presents the screen and records everything drawn since the previous
+// call as one event, timed when the playback loop presented it. Without a
+// copy of the terminal output the whole screen is recorded as a frame.
+func (p *Player) show() {
+	p.screen.Show()
+	if p.recorder == nil {
+		return
+	}
+	var err error
+	if p.recordScreen {
+		err = p.recorder.WriteFrame(p.screenFrame(), time.Since(p.recordStart))
+	} else {
+		err = p.recorder.Flush(time.Since(p.recordStart))
+	}
+	if err != nil {
+		log.Printf("failed to record frame: %v", err)
+	}
+}
+
+// screenFrame returns what the screen shows
+func (p *Player) screenFrame() *media.Frame {
+	width, height := p.screen.Size()
+	frame := media.NewFrame(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, _, style, _ := p.screen.GetContent(x, y)
+			fg, bg, attrs := style.Decompose()
+			cell := media.Cell{Rune: r, Fg: mediaColor(fg), Bg: mediaColor(bg)}
+			if attrs&tcell.AttrReverse != 0 {
+				cell.Attrs |= media.AttrReverse
+			}
+			if attrs&tcell.AttrBold != 0 {
+				cell.Attrs |= media.AttrBold
+			}
+			frame.Set(x, y, cell)
+		}
+	}
+	return frame
+}
+
+// recordResize records a change of the terminal size
+func (p *Player) recordResize(width, height int) {
+	if p.recorder == nil {
+		return
+	}
+	if err := p.recorder.Resize(time.Since(p.recordStart), width, height); err != nil {
+		log.Printf("failed to record resize: %v", err)
+	}
+}
+
+// closeRecorder finishes the recording, including the output that restored the terminal
+func (p *Player) closeRecorder() {
+	if p.recorder == nil {
+		return
+	}
+	if err := p.recorder.Close(); err != nil {
+		log.Printf("failed to finish recording: %v", err)
+	}
+}
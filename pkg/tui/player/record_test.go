@@ -0,0 +1,37 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+func TestScreenFrame(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(3, 1)
+
+	want := []media.Cell{
+		{Rune: 'a', Fg: media.NewRGBColor(0xff, 0x80, 0), Bg: media.NewIndexColor(4)},
+		{Rune: 'b', Attrs: media.AttrReverse | media.AttrBold},
+		{Rune: ' '},
+	}
+	for x, cell := range want[:2] {
+		screen.SetContent(x, 0, cell.Rune, nil, cellStyle(cell))
+	}
+
+	p := &Player{screen: screen}
+	frame := p.screenFrame()
+	if frame.Width != 3 || frame.Height != 1 {
+		t.Fatalf("got a %dx%d frame, want 3x1", frame.Width, frame.Height)
+	}
+	for x, cell := range want {
+		if got := frame.At(x, 0); got != cell {
+			t.Errorf("cell %d is %+v, want %+v", x, got, cell)
+		}
+	}
+}
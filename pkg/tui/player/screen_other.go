@@ -0,0 +1,16 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos)
+
+package player
+
+import (
+	"io"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newScreen creates tcell's default screen. Output is not measured or copied
+// to record on this platform, so recordings are made from the presented screen.
+func newScreen(record io.Writer) (tcell.Screen, *byteCounter, error) {
+	screen, err := tcell.NewScreen()
+	return screen, nil, err
+}
@@ -0,0 +1,29 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package player
+
+import (
+	"io"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newScreen creates a screen on /dev/tty whose output is measured by the
+// returned counter, falling back to tcell's default screen without a counter.
+// Everything written to the terminal is copied to record when it is not nil;
+// without a counter nothing is copied.
+func newScreen(record io.Writer) (tcell.Screen, *byteCounter, error) {
+	tty, err := tcell.NewDevTty()
+	if err != nil {
+		screen, err := tcell.NewScreen()
+		return screen, nil, err
+	}
+
+	counter := &byteCounter{}
+	screen, err := tcell.NewTerminfoScreenFromTty(&countingTty{Tty: tty, counter: counter, record: record})
+	if err != nil {
+		tty.Close()
+		return nil, nil, err
+	}
+	return screen, counter, nil
+}
@@ -0,0 +1,152 @@
+package player
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"github.com/kweonminsung/console-cinema/pkg/visualizer"
+)
+
+// thumbnailRequest asks for a thumbnail of the given position and cell size
+type thumbnailRequest struct {
+	position time.Duration
+	width    int
+	height   int
+}
+
+// thumbnailer renders timeline previews from its own FrameExtractor so
+// seeking for a thumbnail never disturbs the main playback. Requests are
+// handled one at a time and only the most recent pending one is kept.
+type thumbnailer struct {
+	config   types.PlayerConfig
+	requests chan thumbnailRequest
+	// quit is closed by Close instead of requests, so Request stays safe to
+	// call from input handlers that race with shutdown
+	quit      chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+
+	mutex    sync.Mutex
+	frame    *media.Frame
+	position time.Duration
+
+	// extractor and renderer are opened lazily and only used by run
+	extractor *media.FrameExtractor
+	renderer  media.Renderer
+}
+
+// newThumbnailer creates a thumbnailer for the source in config and starts its worker
+func newThumbnailer(config types.PlayerConfig) *thumbnailer {
+	t := &thumbnailer{
+		config:   config,
+		requests: make(chan thumbnailRequest, 1),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Request asks for a thumbnail, replacing any request that has not been
+// started yet. Requests made after Close are dropped.
+func (t *thumbnailer) Request(position time.Duration, width, height int) {
+	request := thumbnailRequest{position: position, width: width, height: height}
+	for {
+		select {
+		case <-t.quit:
+			return
+		case t.requests <- request:
+			return
+		default:
+		}
+		select {
+		case <-t.requests:
+		default:
+		}
+	}
+}
+
+// Thumbnail returns the most recently rendered thumbnail and its position
+func (t *thumbnailer) Thumbnail() (*media.Frame, time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.frame, t.position
+}
+
+// Close stops the worker and releases the extractor. It may be called more than once.
+func (t *thumbnailer) Close() {
+	t.closeOnce.Do(func() { close(t.quit) })
+	<-t.done
+}
+
+func (t *thumbnailer) run() {
+	defer close(t.done)
+	defer func() {
+		if t.extractor != nil {
+			t.extractor.Close()
+		}
+	}()
+
+	for {
+		var request thumbnailRequest
+		select {
+		case <-t.quit:
+			return
+		case request = <-t.requests:
+		}
+
+		if err := t.open(); err != nil {
+			// Request never blocks on a full channel, so nothing has to drain it
+			log.Printf("thumbnails disabled: %v", err)
+			return
+		}
+
+		frame, err := t.render(request)
+		if err != nil {
+			log.Printf("failed to render thumbnail at %v: %v", request.position, err)
+			continue
+		}
+
+		t.mutex.Lock()
+		t.frame, t.position = frame, request.position
+		t.mutex.Unlock()
+	}
+}
+
+// open creates the extractor and renderer on first use
+func (t *thumbnailer) open() error {
+	if t.extractor != nil {
+		return nil
+	}
+	if t.config.Mode == visualizer.ModeName {
+		return fmt.Errorf("%s mode shows no video", visualizer.ModeName)
+	}
+
+	renderer, err := media.NewRenderer(t.config.Mode, t.config)
+	if err != nil {
+		return err
+	}
+	extractor, err := media.NewFrameExtractor(t.config.Source, t.config.IsYouTube)
+	if err != nil {
+		return err
+	}
+	// Thumbnails only need to be close to the hovered time
+	extractor.SetSeekMode(media.SeekFast)
+
+	t.extractor, t.renderer = extractor, renderer
+	return nil
+}
+
+func (t *thumbnailer) render(request thumbnailRequest) (*media.Frame, error) {
+	img, err := t.extractor.GetFrameAt(request.position)
+	if err != nil {
+		return nil, err
+	}
+	defer img.Close()
+
+	return t.renderer.Convert(img, request.width, request.height, t.config.Color)
+}
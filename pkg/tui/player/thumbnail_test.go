@@ -0,0 +1,39 @@
+package player
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/types"
+)
+
+// disabledConfig makes the thumbnailer fail to open, so its worker stops on the first request
+var disabledConfig = types.PlayerConfig{Mode: "no-such-mode"}
+
+func TestThumbnailerRequestAfterClose(t *testing.T) {
+	thumbs := newThumbnailer(disabledConfig)
+	thumbs.Request(time.Second, 10, 5)
+	thumbs.Close()
+
+	// Neither may panic or block once the worker has stopped
+	thumbs.Request(2*time.Second, 10, 5)
+	thumbs.Close()
+}
+
+func TestThumbnailerRequestDuringClose(t *testing.T) {
+	thumbs := newThumbnailer(disabledConfig)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				thumbs.Request(time.Duration(j)*time.Millisecond, 10, 5)
+			}
+		}()
+	}
+	thumbs.Close()
+	wg.Wait()
+}
@@ -0,0 +1,221 @@
+package player
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kweonminsung/console-cinema/pkg/utils"
+)
+
+// statusBarHeight is the number of rows below the video: the timeline and two lines of text
+const statusBarHeight = 3
+
+// Timeline runes for the played, buffered and remaining parts of the video
+const (
+	timelinePlayed    = '█'
+	timelineBuffered  = '▓'
+	timelineRemaining = '░'
+)
+
+// timelineLabelWidth is the width of a "hh:mm:ss " label on either side of the bar
+const timelineLabelWidth = 9
+
+// rect is a rectangle of screen cells
+type rect struct {
+	x, y, width, height int
+}
+
+// statusTop returns the first row of the status bar
+func (p *Player) statusTop() int {
+	_, screenHeight := p.screen.Size()
+	y := p.height + p.stripHeight()
+	if y > screenHeight-statusBarHeight {
+		y = screenHeight - statusBarHeight
+	}
+	if y < 0 {
+		y = 0
+	}
+	return y
+}
+
+// timelineBar returns the row of the timeline and the first and last column of its bar
+func (p *Player) timelineBar() (y, x0, x1 int) {
+	width, _ := p.screen.Size()
+	return p.statusTop(), timelineLabelWidth, width - timelineLabelWidth - 1
+}
+
+// timelinePosition maps a column of the bar to a position in the video
+func (p *Player) timelinePosition(x int) time.Duration {
+	_, x0, x1 := p.timelineBar()
+	if x1 <= x0 || p.videoPlayer == nil {
+		return 0
+	}
+	if x < x0 {
+		x = x0
+	}
+	if x > x1 {
+		x = x1
+	}
+	return p.videoPlayer.GetDuration() * time.Duration(x-x0) / time.Duration(x1-x0)
+}
+
+// timelineColumn maps a position in the video to a column of the bar
+func (p *Player) timelineColumn(position time.Duration) int {
+	_, x0, x1 := p.timelineBar()
+	duration := p.videoPlayer.GetDuration()
+	if x1 <= x0 || duration <= 0 {
+		return x0
+	}
+	return clamp(x0+int(int64(x1-x0)*int64(position)/int64(duration)), x0, x1)
+}
+
+// drawTimeline draws the progress bar with the played and buffered ranges
+func (p *Player) drawTimeline(style tcell.Style) {
+	y, x0, x1 := p.timelineBar()
+	duration := p.videoPlayer.GetDuration()
+	position := p.videoPlayer.GetPosition()
+	buffered := p.videoPlayer.GetBufferedPosition()
+
+	p.drawStyledString(0, y, utils.FormatDuration(position)+" ", style)
+	p.drawStyledString(x1+1, y, " "+utils.FormatDuration(duration), style)
+
+	for x := x0; x <= x1; x++ {
+		at := p.timelinePosition(x)
+		r := timelineRemaining
+		switch {
+		case at <= position:
+			r = timelinePlayed
+		case at <= buffered:
+			r = timelineBuffered
+		}
+		p.screen.SetContent(x, y, r, nil, style)
+	}
+	p.drawMarkers(style)
+}
+
+// handleMouse shows a thumbnail while the pointer hovers or drags over the
+// timeline and seeks to the chosen position when the button is released
+func (p *Player) handleMouse(ev *tcell.EventMouse) {
+	x, y := ev.Position()
+	barY, x0, x1 := p.timelineBar()
+	onBar := y == barY && x >= x0 && x <= x1
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	switch {
+	case ev.Buttons()&tcell.Button1 != 0 && (onBar || p.dragging):
+		p.dragging = true
+		p.hover(x)
+	case p.dragging:
+		// Button released after a click or drag
+		p.dragging = false
+		p.seekTo(p.hoverPosition)
+		p.hovering = onBar
+	case onBar:
+		p.hover(x)
+	default:
+		p.hovering = false
+	}
+}
+
+// hover records the hovered timeline column and requests its thumbnail.
+// The caller must hold p.mutex.
+func (p *Player) hover(x int) {
+	p.hovering = true
+	p.hoverX = x
+	p.hoverPosition = p.timelinePosition(x)
+
+	if p.thumbnails != nil {
+		width, height := p.width/4, p.height/3
+		p.thumbnails.Request(p.hoverPosition, clamp(width, 16, 48), clamp(height, 4, 16))
+	}
+}
+
+// drawOverlays redraws the popups over the video. The cells under the
+// previous popups are restored from the drawn frame first.
+func (p *Player) drawOverlays() {
+	p.restoreRegion(p.overlay)
+	p.restoreRegion(p.bookmarkList)
+	p.overlay, p.bookmarkList = rect{}, rect{}
+
+	p.drawBookmarkList()
+	p.drawThumbnail()
+}
+
+// drawThumbnail pops up the thumbnail of the hovered position above the timeline
+func (p *Player) drawThumbnail() {
+	p.mutex.Lock()
+	hovering, hoverX := p.hovering, p.hoverX
+	p.mutex.Unlock()
+	if !hovering || p.thumbnails == nil {
+		return
+	}
+
+	frame, position := p.thumbnails.Thumbnail()
+	if frame == nil {
+		return
+	}
+	if p.quantizer != nil {
+		frame = p.quantizer.QuantizeFrame(frame)
+	}
+
+	screenWidth, _ := p.screen.Size()
+	box := rect{width: frame.Width + 2, height: frame.Height + 2}
+	box.x = clamp(hoverX-box.width/2, 0, screenWidth-box.width)
+	box.y = p.statusTop() - box.height
+	if box.y < 0 || box.x < 0 {
+		return
+	}
+
+	style := tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
+	for i := box.x; i < box.x+box.width; i++ {
+		p.screen.SetContent(i, box.y, tcell.RuneHLine, nil, style)
+		p.screen.SetContent(i, box.y+box.height-1, tcell.RuneHLine, nil, style)
+	}
+	for i := box.y; i < box.y+box.height; i++ {
+		p.screen.SetContent(box.x, i, tcell.RuneVLine, nil, style)
+		p.screen.SetContent(box.x+box.width-1, i, tcell.RuneVLine, nil, style)
+	}
+	p.screen.SetContent(box.x, box.y, tcell.RuneULCorner, nil, style)
+	p.screen.SetContent(box.x+box.width-1, box.y, tcell.RuneURCorner, nil, style)
+	p.screen.SetContent(box.x, box.y+box.height-1, tcell.RuneLLCorner, nil, style)
+	p.screen.SetContent(box.x+box.width-1, box.y+box.height-1, tcell.RuneLRCorner, nil, style)
+
+	label := " " + utils.FormatDuration(position) + " "
+	if len(label) <= frame.Width {
+		p.drawStyledString(box.x+(box.width-len(label))/2, box.y, label, style)
+	}
+
+	for y := 0; y < frame.Height; y++ {
+		for x, cell := range frame.Row(y) {
+			p.screen.SetContent(box.x+1+x, box.y+1+y, cell.Rune, nil, cellStyle(cell))
+		}
+	}
+	p.overlay = box
+}
+
+// restoreRegion redraws the cells of the drawn frame inside r
+func (p *Player) restoreRegion(r rect) {
+	for y := r.y; y < r.y+r.height; y++ {
+		for x := r.x; x < r.x+r.width; x++ {
+			if p.drawn != nil && x < p.drawn.Width && y < p.drawn.Height {
+				cell := p.drawn.At(x, y)
+				p.screen.SetContent(x, y, cell.Rune, nil, cellStyle(cell))
+			} else {
+				p.screen.SetContent(x, y, ' ', nil, tcell.StyleDefault)
+			}
+		}
+	}
+}
+
+// clamp limits v to the range [lo, hi]
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
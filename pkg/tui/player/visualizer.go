@@ -0,0 +1,84 @@
+package player
+
+import (
+	"log"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/video"
+	"github.com/kweonminsung/console-cinema/pkg/visualizer"
+)
+
+// layout sizes the video area for a screen of the given size, leaving room
+// for the status bar and the visualizer strip
+func (p *Player) layout(width, height int) {
+	p.width, p.height = width, height-statusBarHeight-p.stripHeight()
+}
+
+// stripHeight returns the rows of the visualizer strip under the video. The
+// strip is not shown in visualizer mode or the audio-only view, and always
+// leaves the video one row.
+func (p *Player) stripHeight() int {
+	if p.mode == visualizer.ModeName || p.audioOnly || p.config.VisualizerStrip <= 0 || p.screen == nil {
+		return 0
+	}
+	_, height := p.screen.Size()
+	return max(0, min(p.config.VisualizerStrip, height-statusBarHeight-1))
+}
+
+// newBlankVideoPlayer creates a video player whose frames are blank and only
+// pace playback over the length of the audio, for when there is no video to
+// show. What is drawn in their place is decided when they are drawn.
+func (p *Player) newBlankVideoPlayer() (*video.VideoPlayer, error) {
+	duration, err := p.audioPlayer.Duration()
+	if err != nil {
+		return nil, err
+	}
+	renderer, err := media.NewRenderer(visualizer.ModeName, p.config)
+	if err != nil {
+		return nil, err
+	}
+	source := media.NewBlankSource(duration, float64(p.fps))
+	return video.NewVideoPlayerWithSource(source, renderer, p.config), nil
+}
+
+// setupVisualizer creates the visualizer for the audio being played. Without
+// audio the strip stays empty.
+func (p *Player) setupVisualizer() {
+	if p.audioPlayer == nil {
+		log.Printf("no audio to visualize")
+		return
+	}
+	style, _ := visualizer.ParseStyle(p.config.Visualizer)
+	p.visualizer = visualizer.New(style, p.audioPlayer.SampleRate(), p.color)
+	p.visSamples = make([][2]float64, visualizer.WindowSize)
+}
+
+// visualize draws the audio playing now over the frame: in place of it in
+// visualizer mode, or in the strip under it. The audio-only view replaces the
+// frame, which may then be nil.
+func (p *Player) visualize(frame *media.Frame) *media.Frame {
+	if p.audioOnly {
+		return p.drawAudioOnly()
+	}
+	if p.visualizer == nil {
+		return frame
+	}
+	p.audioPlayer.Recent(p.visSamples)
+	if p.mode == visualizer.ModeName {
+		return p.visualizer.Draw(p.visSamples, p.width, p.height)
+	}
+
+	strip := p.stripHeight()
+	if strip == 0 {
+		return frame
+	}
+	composed := media.NewFrame(max(frame.Width, p.width), frame.Height+strip)
+	for y := 0; y < frame.Height; y++ {
+		copy(composed.Row(y), frame.Row(y))
+	}
+	drawing := p.visualizer.Draw(p.visSamples, composed.Width, strip)
+	for y := 0; y < strip; y++ {
+		copy(composed.Row(frame.Height+y), drawing.Row(y))
+	}
+	return composed
+}
@@ -11,7 +11,58 @@ type PlayerConfig struct {
 	FPS       int
 	Width     int
 	Height    int
-	Loop      bool
+	Loop      string
 	Source    string
 	IsYouTube bool
+
+	// Line mode options
+	LineThreshold float64
+	LineCharset   string
+	LineFill      bool
+
+	// ASCII mode charset: a preset name or a custom string, ordered dark to bright
+	Charset          string
+	InvertCharset    bool
+	CalibrateCharset bool
+
+	// Dither selects the binarization used by braille mode (fs, ordered, threshold)
+	Dither string
+
+	// ColorDepth overrides the detected terminal color depth (auto, truecolor, 256, 16, 8, mono)
+	ColorDepth  string
+	ColorDither bool
+
+	// ColorTolerance is the per-channel difference below which a redrawn cell keeps its old color
+	ColorTolerance int
+
+	// Speed is the initial playback speed; audio keeps its pitch
+	Speed float64
+
+	// SeekMode selects frame-accurate or fast seeking (accurate, fast)
+	SeekMode string
+
+	// PrefetchDepth is the number of frames buffered between the decode, convert and display stages
+	PrefetchDepth int
+	// RenderWorkers is the number of frames converted concurrently; 0 picks a default
+	RenderWorkers int
+
+	// Record is the asciicast v2 file the terminal output is recorded to; empty disables recording
+	Record string
+
+	// Volume is the initial audio volume in percent, from 0 to 200
+	Volume int
+
+	// AudioOut is where audio goes: speaker, null or a .wav file to write it to
+	AudioOut string
+
+	// AudioFile is a separate soundtrack played instead of the audio of the source; empty uses the source
+	AudioFile string
+
+	// Visualizer is the style of the audio visualizer (spectrum, waveform)
+	Visualizer string
+	// VisualizerStrip is the number of rows under the video given to the visualizer; 0 disables the strip
+	VisualizerStrip int
+
+	// SextantFallback renders sextant mode with quadrant glyphs for fonts lacking sextants
+	SextantFallback bool
 }
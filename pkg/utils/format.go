@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,3 +17,40 @@ func FormatDuration(d time.Duration) string {
 	s := d / time.Second
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
 }
+
+// FormatByteRate formats a bytes-per-second rate with a binary unit suffix.
+func FormatByteRate(bytesPerSecond float64) string {
+	units := []string{"B/s", "KiB/s", "MiB/s", "GiB/s"}
+	unit := 0
+	for bytesPerSecond >= 1024 && unit < len(units)-1 {
+		bytesPerSecond /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", bytesPerSecond, units[unit])
+}
+
+// ParseTimestamp parses a position given as seconds ("90", "90.5"), as
+// [hh:]mm:ss[.fff] ("01:30", "1:02:03.5") or as a Go duration ("1m30s").
+func ParseTimestamp(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var seconds float64
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		seconds = seconds*60 + value
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,48 @@
+package video
+
+// reverseCacheFrames is the number of rendered frames kept for backward playback
+const reverseCacheFrames = 120
+
+// frameCache is a ring buffer of the most recently rendered frames. Forward
+// playback fills it so a change of direction can replay those frames
+// immediately, and backward playback refills it one decoded segment at a time.
+type frameCache struct {
+	frames []RenderedFrame
+	start  int
+	size   int
+}
+
+func newFrameCache(capacity int) *frameCache {
+	return &frameCache{frames: make([]RenderedFrame, capacity)}
+}
+
+// Push adds a frame as the newest entry, evicting the oldest one when full
+func (c *frameCache) Push(frame RenderedFrame) {
+	end := (c.start + c.size) % len(c.frames)
+	c.frames[end] = frame
+	if c.size < len(c.frames) {
+		c.size++
+	} else {
+		c.start = (c.start + 1) % len(c.frames)
+	}
+}
+
+// PopNewest removes and returns the newest frame
+func (c *frameCache) PopNewest() (RenderedFrame, bool) {
+	if c.size == 0 {
+		return RenderedFrame{}, false
+	}
+	c.size--
+	i := (c.start + c.size) % len(c.frames)
+	frame := c.frames[i]
+	c.frames[i] = RenderedFrame{}
+	return frame, true
+}
+
+// Clear removes every frame
+func (c *frameCache) Clear() {
+	for c.size > 0 {
+		c.PopNewest()
+	}
+	c.start = 0
+}
@@ -0,0 +1,84 @@
+package video
+
+import (
+	"testing"
+)
+
+// popAll empties the cache and returns the frame numbers from newest to oldest
+func popAll(c *frameCache) []int {
+	var numbers []int
+	for {
+		frame, ok := c.PopNewest()
+		if !ok {
+			return numbers
+		}
+		numbers = append(numbers, frame.FrameNumber)
+	}
+}
+
+func TestFrameCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		pushes int
+		want   []int
+	}{
+		{"empty", 0, nil},
+		{"partly filled", 3, []int{2, 1, 0}},
+		{"full", 4, []int{3, 2, 1, 0}},
+		{"evicts the oldest", 6, []int{5, 4, 3, 2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newFrameCache(4)
+			for i := 0; i < test.pushes; i++ {
+				c.Push(RenderedFrame{FrameNumber: i})
+			}
+			got := popAll(c)
+			if len(got) != len(test.want) {
+				t.Fatalf("popped %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("popped %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFrameCachePushAfterPop(t *testing.T) {
+	c := newFrameCache(3)
+	for i := 0; i < 5; i++ {
+		c.Push(RenderedFrame{FrameNumber: i})
+	}
+	c.PopNewest()
+	c.Push(RenderedFrame{FrameNumber: 10})
+	c.Push(RenderedFrame{FrameNumber: 11})
+
+	want := []int{11, 10, 3}
+	got := popAll(c)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+}
+
+func TestFrameCacheClear(t *testing.T) {
+	c := newFrameCache(3)
+	for i := 0; i < 5; i++ {
+		c.Push(RenderedFrame{FrameNumber: i})
+	}
+	c.Clear()
+	if _, ok := c.PopNewest(); ok {
+		t.Fatal("a frame is left after Clear")
+	}
+	for _, frame := range c.frames {
+		if frame != (RenderedFrame{}) {
+			t.Errorf("Clear kept a reference to frame %d", frame.FrameNumber)
+		}
+	}
+
+	c.Push(RenderedFrame{FrameNumber: 7})
+	if got := popAll(c); len(got) != 1 || got[0] != 7 {
+		t.Errorf("popped %v after Clear, want [7]", got)
+	}
+}
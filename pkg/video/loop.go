@@ -0,0 +1,33 @@
+package video
+
+import "fmt"
+
+// LoopMode selects what happens when playback reaches the end of the video
+type LoopMode string
+
+const (
+	// LoopOff stops at the end of the video
+	LoopOff LoopMode = "off"
+	// LoopRestart starts again from the beginning
+	LoopRestart LoopMode = "restart"
+	// LoopPingPong alternates between playing forward and backward
+	LoopPingPong LoopMode = "pingpong"
+	// LoopReverse plays the video backward, jumping back to the end when the start is reached
+	LoopReverse LoopMode = "reverse"
+)
+
+// LoopModes lists every supported loop mode
+var LoopModes = []LoopMode{LoopOff, LoopRestart, LoopPingPong, LoopReverse}
+
+// ParseLoopMode converts a string to a LoopMode. An empty string disables looping.
+func ParseLoopMode(s string) (LoopMode, error) {
+	if s == "" {
+		return LoopOff, nil
+	}
+	for _, mode := range LoopModes {
+		if LoopMode(s) == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown loop mode %q (available: %v)", s, LoopModes)
+}
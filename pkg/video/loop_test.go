@@ -0,0 +1,27 @@
+package video
+
+import (
+	"testing"
+)
+
+func TestParseLoopMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LoopMode
+		wantErr bool
+	}{
+		{"", LoopOff, false},
+		{"off", LoopOff, false},
+		{"restart", LoopRestart, false},
+		{"pingpong", LoopPingPong, false},
+		{"reverse", LoopReverse, false},
+		{"true", "", true},
+		{"Restart", "", true},
+	}
+	for _, test := range tests {
+		got, err := ParseLoopMode(test.input)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("ParseLoopMode(%q) = %q, %v", test.input, got, err)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"gocv.io/x/gocv"
+)
+
+const (
+	// DefaultPrefetchDepth is the number of frames buffered between pipeline stages
+	DefaultPrefetchDepth = 8
+	// maxRenderWorkers caps the automatic converter worker count, as most
+	// converters already split each frame across rows internally
+	maxRenderWorkers = 4
+)
+
+// RenderedFrame is a frame delivered by the pipeline, in decode order
+type RenderedFrame struct {
+	Frame *media.Frame
+	// Position is the source timestamp of the frame
+	Position time.Duration
+	// FrameNumber is the source frame index of the frame
+	FrameNumber int
+	// Err is set on the last value sent when decoding or converting failed
+	Err error
+}
+
+// decodeJob carries a decoded image from the decoder to a converter worker
+type decodeJob struct {
+	seq         int
+	img         gocv.Mat
+	position    time.Duration
+	frameNumber int
+	// end marks the end of the stream; img is not set
+	end bool
+}
+
+// convertResult is a converted frame tagged with its decode sequence number
+type convertResult struct {
+	seq   int
+	frame RenderedFrame
+}
+
+// Pipeline decodes frames on one goroutine, converts them on a pool of
+// workers and delivers them in order on a bounded channel. A full output
+// channel blocks the workers, which in turn blocks the decoder.
+type Pipeline struct {
+	source   media.FrameSource
+	renderer media.Renderer
+	color    bool
+
+	sizeMu sync.RWMutex
+	width  int
+	height int
+
+	// decoded is the position of the most recently decoded frame
+	decoded atomic.Int64
+
+	out    chan RenderedFrame
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPipeline creates and starts a pipeline reading from the current position of source.
+// depth is the prefetch depth and workers the number of converter goroutines;
+// values of zero or less select the defaults.
+func NewPipeline(source media.FrameSource, renderer media.Renderer, width, height int, color bool, depth, workers int) *Pipeline {
+	if depth <= 0 {
+		depth = DefaultPrefetchDepth
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+		if workers > maxRenderWorkers {
+			workers = maxRenderWorkers
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pipeline{
+		source:   source,
+		renderer: renderer,
+		color:    color,
+		width:    width,
+		height:   height,
+		out:      make(chan RenderedFrame, depth),
+		cancel:   cancel,
+	}
+
+	jobs := make(chan decodeJob, depth)
+	results := make(chan convertResult, depth)
+
+	p.wg.Add(1)
+	go p.decode(ctx, jobs)
+
+	var workerWg sync.WaitGroup
+	workerWg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer workerWg.Done()
+			p.convert(ctx, jobs, results)
+		}()
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		workerWg.Wait()
+		close(results)
+	}()
+
+	p.wg.Add(1)
+	go p.reorder(ctx, results)
+
+	return p
+}
+
+// Frames returns the channel delivering converted frames in order.
+// The channel is closed after the last frame, an error, or Stop.
+func (p *Pipeline) Frames() <-chan RenderedFrame {
+	return p.out
+}
+
+// Resize changes the cell size used for frames converted from now on
+func (p *Pipeline) Resize(width, height int) {
+	p.sizeMu.Lock()
+	defer p.sizeMu.Unlock()
+	p.width, p.height = width, height
+}
+
+// Buffered returns the position of the most recently decoded frame, which
+// marks the end of the range buffered ahead of playback
+func (p *Pipeline) Buffered() time.Duration {
+	return time.Duration(p.decoded.Load())
+}
+
+// Stop cancels every stage and waits until none of them touches the source anymore
+func (p *Pipeline) Stop() {
+	p.cancel()
+	// Unblock the reorder stage if nobody is reading the output
+	for range p.out {
+	}
+	p.wg.Wait()
+}
+
+// decode reads frames from the source until the end of the stream or cancellation
+func (p *Pipeline) decode(ctx context.Context, jobs chan<- decodeJob) {
+	defer p.wg.Done()
+	defer close(jobs)
+
+	for seq := 0; ; seq++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		img, err := p.source.ReadNextFrame()
+		if err != nil {
+			// An empty job marks the end of the stream
+			select {
+			case jobs <- decodeJob{seq: seq, end: true}:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		job := decodeJob{
+			seq:         seq,
+			img:         img,
+			position:    p.source.GetPosition(),
+			frameNumber: p.source.GetCurrentFrame(),
+		}
+		p.decoded.Store(int64(job.position))
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			img.Close()
+			return
+		}
+	}
+}
+
+// convert renders decoded images with the renderer
+func (p *Pipeline) convert(ctx context.Context, jobs <-chan decodeJob, results chan<- convertResult) {
+	for job := range jobs {
+		if ctx.Err() != nil {
+			// Keep draining so no decoded image is left unreleased
+			if !job.end {
+				job.img.Close()
+			}
+			continue
+		}
+
+		result := convertResult{seq: job.seq}
+		if job.end {
+			result.frame.Err = fmt.Errorf("end of stream")
+		} else {
+			p.sizeMu.RLock()
+			width, height := p.width, p.height
+			p.sizeMu.RUnlock()
+
+			frame, err := p.renderer.Convert(job.img, width, height, p.color)
+			job.img.Close()
+			if err != nil {
+				err = fmt.Errorf("failed to convert frame %d: %v", job.frameNumber, err)
+			}
+			result.frame = RenderedFrame{
+				Frame:       frame,
+				Position:    job.position,
+				FrameNumber: job.frameNumber,
+				Err:         err,
+			}
+		}
+
+		select {
+		case results <- result:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// reorder restores decode order before handing frames to the presenter
+func (p *Pipeline) reorder(ctx context.Context, results <-chan convertResult) {
+	defer p.wg.Done()
+	defer close(p.out)
+
+	pending := make(map[int]RenderedFrame)
+	next := 0
+	for result := range results {
+		pending[result.seq] = result.frame
+		for {
+			frame, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			select {
+			case p.out <- frame:
+			case <-ctx.Done():
+				drainResults(results)
+				return
+			}
+			if frame.Err != nil {
+				p.cancel()
+				drainResults(results)
+				return
+			}
+		}
+	}
+}
+
+// drainResults discards results until the converter workers have exited
+func drainResults(results <-chan convertResult) {
+	for range results {
+	}
+}
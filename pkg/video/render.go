@@ -0,0 +1,86 @@
+package video
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+)
+
+// FrameSink receives the frames written by Render
+type FrameSink interface {
+	// WriteFrame writes a frame together with its source timestamp
+	WriteFrame(frame *media.Frame, position time.Duration) error
+}
+
+// TextSink writes frames as text in a FrameFormat, each followed by an empty line
+type TextSink struct {
+	out    *bufio.Writer
+	format media.FrameFormat
+}
+
+// NewTextSink creates a TextSink writing to w. Call Flush when done.
+func NewTextSink(w io.Writer, format media.FrameFormat) *TextSink {
+	return &TextSink{out: bufio.NewWriter(w), format: format}
+}
+
+// WriteFrame implements FrameSink
+func (s *TextSink) WriteFrame(frame *media.Frame, position time.Duration) error {
+	_, err := io.WriteString(s.out, frame.Encode(s.format)+"\n")
+	return err
+}
+
+// Flush writes any buffered output
+func (s *TextSink) Flush() error {
+	return s.out.Flush()
+}
+
+// RenderOptions selects which frames Render writes
+type RenderOptions struct {
+	// Start is the position of the first frame; End stops rendering when
+	// non-zero, and Frames limits the number of frames when non-zero
+	Start  time.Duration
+	End    time.Duration
+	Frames int
+	// Quantizer maps frame colors onto a limited palette; nil keeps them as they are
+	Quantizer *media.Quantizer
+}
+
+// Render hands consecutive frames to sink as fast as they are decoded,
+// without clearing or taking over the terminal, so the output can be piped,
+// logged or recorded. It returns the number of frames written.
+func (p *VideoPlayer) Render(sink FrameSink, options RenderOptions) (int, error) {
+	if options.End > 0 && options.End <= options.Start {
+		return 0, fmt.Errorf("end %v must be after start %v", options.End, options.Start)
+	}
+	if options.Start > 0 {
+		p.SeekTo(options.Start)
+	}
+
+	written := 0
+	for options.Frames <= 0 || written < options.Frames {
+		frame, err := p.GetNextFrame()
+		if err != nil {
+			if written == 0 {
+				return 0, fmt.Errorf("no frames to render: %v", err)
+			}
+			// The end of the video ends the output
+			break
+		}
+		position := p.GetPosition()
+		if options.End > 0 && position >= options.End {
+			break
+		}
+
+		if options.Quantizer != nil {
+			frame = options.Quantizer.QuantizeFrame(frame)
+		}
+		if err := sink.WriteFrame(frame, position); err != nil {
+			return written, fmt.Errorf("failed to write frame: %v", err)
+		}
+		written++
+	}
+	return written, nil
+}
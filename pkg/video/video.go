@@ -0,0 +1,438 @@
+package video
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/types"
+)
+
+// VideoPlayer reads frames from a FrameSource and converts them with a Renderer.
+// Sequential playback goes through a Pipeline that is started lazily and
+// restarted after every seek. Backward playback replays recently rendered
+// frames from a ring cache and refills it by decoding the preceding segment.
+type VideoPlayer struct {
+	source   media.FrameSource
+	renderer media.Renderer
+	config   types.PlayerConfig
+
+	mutex    sync.Mutex
+	pipeline *Pipeline
+	closed   bool
+
+	// sourceMutex serializes seeking and decoding the source outside the
+	// pipeline, so a backward segment is decoded without holding mutex and the
+	// position stays readable meanwhile. It is always taken before mutex.
+	sourceMutex sync.Mutex
+	// generation changes with every seek and change of direction, so a segment
+	// decoded in the meantime is thrown away
+	generation int
+
+	// position and frameNumber describe the last frame handed out by GetNextFrame
+	position    time.Duration
+	frameNumber int
+
+	backward bool
+	cache    *frameCache
+}
+
+// NewVideoPlayer creates a new video player that renders frames with the
+// renderer registered under config.Mode
+func NewVideoPlayer(source string, config types.PlayerConfig) (*VideoPlayer, error) {
+	renderer, err := media.NewRenderer(config.Mode, config)
+	if err != nil {
+		return nil, err
+	}
+
+	seekMode, err := media.ParseSeekMode(config.SeekMode)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor, err := media.NewFrameExtractor(source, config.IsYouTube)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create frame extractor: %v", err)
+	}
+	extractor.SetSeekMode(seekMode)
+
+	return NewVideoPlayerWithSource(extractor, renderer, config), nil
+}
+
+// NewVideoPlayerWithSource creates a video player from an already opened source and renderer
+func NewVideoPlayerWithSource(source media.FrameSource, renderer media.Renderer, config types.PlayerConfig) *VideoPlayer {
+	return &VideoPlayer{
+		source:      source,
+		renderer:    renderer,
+		config:      config,
+		position:    source.GetPosition(),
+		frameNumber: source.GetCurrentFrame(),
+		cache:       newFrameCache(reverseCacheFrames),
+	}
+}
+
+// Close stops the pipeline, closes the video player and releases resources
+func (p *VideoPlayer) Close() {
+	p.sourceMutex.Lock()
+	defer p.sourceMutex.Unlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stopPipeline()
+	p.closed = true
+	p.generation++
+	if p.source != nil {
+		p.source.Close()
+	}
+}
+
+// newPipeline starts a pipeline reading from the current source position
+// with the size and settings of config
+func (p *VideoPlayer) newPipeline(config types.PlayerConfig) *Pipeline {
+	return NewPipeline(p.source, p.renderer, config.Width, config.Height, config.Color,
+		config.PrefetchDepth, config.RenderWorkers)
+}
+
+// stopPipeline stops the running pipeline, if any, so the source can be used directly.
+// The caller must hold p.mutex.
+func (p *VideoPlayer) stopPipeline() {
+	if p.pipeline != nil {
+		p.pipeline.Stop()
+		p.pipeline = nil
+	}
+}
+
+// GetFPS returns the FPS of the video
+func (p *VideoPlayer) GetFPS() float64 {
+	return p.source.GetFPS()
+}
+
+// GetFrameAt seeks to a specific time and returns the rendered frame
+func (p *VideoPlayer) GetFrameAt(seekTime time.Duration) (*media.Frame, error) {
+	p.sourceMutex.Lock()
+	defer p.sourceMutex.Unlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.stopPipeline()
+
+	frame, err := p.source.GetFrameAt(seekTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frame at %v: %v", seekTime, err)
+	}
+	defer frame.Close()
+
+	if frame.Empty() {
+		return nil, fmt.Errorf("got empty frame at %v", seekTime)
+	}
+
+	rendered, err := p.renderer.Convert(frame, p.config.Width, p.config.Height, p.config.Color)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert frame in %s mode: %v", p.config.Mode, err)
+	}
+
+	return rendered, nil
+}
+
+// PlayConsecutiveFrames plays consecutive rendered frames in the console
+func (p *VideoPlayer) PlayConsecutiveFrames(frameCount int) error {
+	frameInterval := time.Second / time.Duration(p.GetFPS())
+
+	for i := 0; i < frameCount; i++ {
+		rendered, err := p.GetNextFrame()
+		if err != nil {
+			log.Printf("Stopped at frame %d: %v", i, err)
+			break
+		}
+
+		// Clear terminal and print the frame
+		fmt.Printf("\033[2J\033[H%s", rendered.Markup())
+		time.Sleep(frameInterval)
+	}
+
+	return nil
+}
+
+// PlayFrameAtTime seeks to a specific time and displays the rendered frame
+func (p *VideoPlayer) PlayFrameAtTime(seekTime time.Duration) error {
+	rendered, err := p.GetFrameAt(seekTime)
+	if err != nil {
+		return err
+	}
+
+	// Clear terminal and print the frame
+	fmt.Printf("\033[2J\033[H%s", rendered.Markup())
+	return nil
+}
+
+// GetVideoInfo returns basic information about the video
+func (p *VideoPlayer) GetVideoInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"fps":    p.GetFPS(),
+		"width":  p.config.Width,
+		"height": p.config.Height,
+		"mode":   p.config.Mode,
+	}
+}
+
+// GetVideoWidth returns the original width of the video.
+func (p *VideoPlayer) GetVideoWidth() int {
+	return p.source.GetWidth()
+}
+
+// GetVideoHeight returns the original height of the video.
+func (p *VideoPlayer) GetVideoHeight() int {
+	return p.source.GetHeight()
+}
+
+// UpdateSize updates the player's dimensions.
+// Frames already converted by the pipeline keep their previous size.
+func (p *VideoPlayer) UpdateSize(width, height int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.config.Width = width
+	p.config.Height = height
+	if p.pipeline != nil {
+		p.pipeline.Resize(width, height)
+	}
+}
+
+// GetNextFrame returns the next frame from the pipeline, starting it if needed.
+// It blocks until the frame has been decoded and converted.
+func (p *VideoPlayer) GetNextFrame() (*media.Frame, error) {
+	for {
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			return nil, fmt.Errorf("video player is closed")
+		}
+		if p.backward {
+			if frame, ok := p.nextBackwardFrame(); ok {
+				p.mutex.Unlock()
+				return frame, nil
+			}
+			end, generation := p.position, p.generation
+			p.mutex.Unlock()
+
+			if err := p.decodeSegmentBefore(end, generation); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if p.pipeline == nil {
+			p.pipeline = p.newPipeline(p.config)
+		}
+		pipeline := p.pipeline
+		p.mutex.Unlock()
+
+		rendered, ok := <-pipeline.Frames()
+
+		p.mutex.Lock()
+		if pipeline != p.pipeline {
+			// A seek replaced the pipeline while we were waiting; read from the new one
+			p.mutex.Unlock()
+			continue
+		}
+		if !ok || rendered.Err != nil {
+			// The pipeline ends itself after an error; release it so the next call starts over
+			p.stopPipeline()
+			p.mutex.Unlock()
+			if !ok {
+				return nil, fmt.Errorf("frame pipeline stopped")
+			}
+			return nil, fmt.Errorf("could not get next frame in %s mode: %v", p.config.Mode, rendered.Err)
+		}
+		p.position = rendered.Position
+		p.frameNumber = rendered.FrameNumber
+		p.cache.Push(rendered)
+		p.mutex.Unlock()
+
+		return rendered.Frame, nil
+	}
+}
+
+// Seek seeks the video by the given duration relative to the last returned frame.
+// Frames prefetched from the old position are discarded.
+func (p *VideoPlayer) Seek(duration time.Duration) {
+	p.SeekTo(p.GetPosition() + duration)
+}
+
+// SeekTo seeks the video to the given position from the beginning.
+// Frames prefetched from the old position are discarded.
+func (p *VideoPlayer) SeekTo(position time.Duration) {
+	p.sourceMutex.Lock()
+	defer p.sourceMutex.Unlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.stopPipeline()
+	p.cache.Clear()
+	p.generation++
+
+	if position < 0 {
+		position = 0
+	}
+	if p.backward {
+		// Backward playback decodes the segment before this position on demand
+		p.position = position
+		return
+	}
+	if err := p.source.Seek(position); err != nil {
+		log.Printf("Seek to %v: %v", position, err)
+	}
+	p.position = position
+	p.frameNumber = p.source.GetCurrentFrame()
+}
+
+// GetPosition returns the presentation timestamp of the last frame returned by GetNextFrame.
+func (p *VideoPlayer) GetPosition() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.position
+}
+
+// GetBufferedPosition returns the position up to which frames have been decoded ahead of playback.
+func (p *VideoPlayer) GetBufferedPosition() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.pipeline == nil || p.backward {
+		return p.position
+	}
+	if buffered := p.pipeline.Buffered(); buffered > p.position {
+		return buffered
+	}
+	return p.position
+}
+
+// GetDuration returns the total duration of the video.
+func (p *VideoPlayer) GetDuration() time.Duration {
+	fps := p.GetFPS()
+	if fps <= 0 {
+		return 0
+	}
+	return time.Duration(float64(p.GetTotalFrames()) / fps * float64(time.Second))
+}
+
+// GetCurrentFrame returns the frame number of the last frame returned by GetNextFrame.
+func (p *VideoPlayer) GetCurrentFrame() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.frameNumber
+}
+
+// GetTotalFrames returns the total number of frames in the video.
+func (p *VideoPlayer) GetTotalFrames() int {
+	return p.source.GetTotalFrames()
+}
+
+// SetBackward switches between forward and backward playback, continuing from the last returned frame.
+func (p *VideoPlayer) SetBackward(backward bool) {
+	p.sourceMutex.Lock()
+	defer p.sourceMutex.Unlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.backward == backward {
+		return
+	}
+	p.stopPipeline()
+	p.backward = backward
+	p.generation++
+
+	if !backward {
+		// Resume forward with the frame after the last one returned
+		p.cache.Clear()
+		next := p.position
+		if fps := p.source.GetFPS(); fps > 0 {
+			next += time.Duration(float64(time.Second) / fps)
+		}
+		if err := p.source.Seek(next); err != nil {
+			log.Printf("Seek to %v: %v", next, err)
+		}
+	}
+}
+
+// IsBackward reports whether frames are returned in reverse order.
+func (p *VideoPlayer) IsBackward() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.backward
+}
+
+// nextBackwardFrame returns the cached frame preceding the last returned one,
+// or false when the cache has run out. The caller must hold p.mutex.
+func (p *VideoPlayer) nextBackwardFrame() (*media.Frame, bool) {
+	for {
+		rendered, ok := p.cache.PopNewest()
+		if !ok {
+			return nil, false
+		}
+		if rendered.Position >= p.position {
+			continue // The frame already shown, or one after it
+		}
+
+		p.position = rendered.Position
+		p.frameNumber = rendered.FrameNumber
+		return rendered.Frame, true
+	}
+}
+
+// decodeSegmentBefore renders the frames of the segment ending at end into
+// the cache in forward order. The segment is at most a second long and never
+// holds more frames than the cache. It is decoded without holding p.mutex, and
+// dropped when a seek or change of direction since generation made it stale.
+func (p *VideoPlayer) decodeSegmentBefore(end time.Duration, generation int) error {
+	if end <= 0 {
+		return fmt.Errorf("reached the start of the video")
+	}
+
+	p.sourceMutex.Lock()
+	defer p.sourceMutex.Unlock()
+
+	p.mutex.Lock()
+	stale := p.generation != generation
+	config := p.config
+	p.mutex.Unlock()
+	if stale {
+		return nil
+	}
+
+	segment := time.Second
+	if fps := p.source.GetFPS(); fps > 0 {
+		if limit := time.Duration(float64(reverseCacheFrames) / fps * float64(time.Second)); limit < segment {
+			segment = limit
+		}
+	}
+	start := end - segment
+	if start < 0 {
+		start = 0
+	}
+
+	if err := p.source.Seek(start); err != nil {
+		log.Printf("Seek to %v: %v", start, err)
+	}
+	pipeline := p.newPipeline(config)
+	var frames []RenderedFrame
+	for rendered := range pipeline.Frames() {
+		if rendered.Err != nil || rendered.Position >= end {
+			break
+		}
+		frames = append(frames, rendered)
+	}
+	pipeline.Stop()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.generation != generation {
+		return nil
+	}
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames found before %v", end)
+	}
+	for _, rendered := range frames {
+		p.cache.Push(rendered)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package video
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+// blankRenderer converts every image to an empty frame, after an optional delay
+type blankRenderer struct {
+	delay time.Duration
+}
+
+func (r blankRenderer) Convert(img gocv.Mat, width, height int, color bool) (*media.Frame, error) {
+	time.Sleep(r.delay)
+	return media.NewFrame(width, height), nil
+}
+
+// blankSource is a frame source of blank images at a fixed frame rate
+type blankSource struct {
+	fps         float64
+	totalFrames int
+	next        int
+	position    time.Duration
+}
+
+func (s *blankSource) ReadNextFrame() (gocv.Mat, error) {
+	if s.next >= s.totalFrames {
+		return gocv.Mat{}, fmt.Errorf("end of stream")
+	}
+	s.position = s.frameTime(s.next)
+	s.next++
+	return gocv.NewMatWithSize(2, 2, gocv.MatTypeCV8UC3), nil
+}
+
+func (s *blankSource) GetFrameAt(d time.Duration) (gocv.Mat, error) {
+	s.Seek(d)
+	return s.ReadNextFrame()
+}
+
+func (s *blankSource) Seek(d time.Duration) error {
+	s.next = max(0, min(int(math.Round(d.Seconds()*s.fps)), s.totalFrames))
+	s.position = s.frameTime(s.next)
+	return nil
+}
+
+func (s *blankSource) frameTime(frame int) time.Duration {
+	return time.Duration(float64(frame) / s.fps * float64(time.Second))
+}
+
+func (s *blankSource) GetFPS() float64            { return s.fps }
+func (s *blankSource) GetWidth() int              { return 2 }
+func (s *blankSource) GetHeight() int             { return 2 }
+func (s *blankSource) GetPosition() time.Duration { return s.position }
+func (s *blankSource) GetCurrentFrame() int       { return s.next }
+func (s *blankSource) GetTotalFrames() int        { return s.totalFrames }
+func (s *blankSource) Close()                     {}
+
+func newBlankPlayer(duration time.Duration, fps float64, delay time.Duration) *VideoPlayer {
+	config := types.PlayerConfig{Width: 4, Height: 2}
+	source := &blankSource{fps: fps, totalFrames: int(math.Ceil(duration.Seconds() * fps))}
+	return NewVideoPlayerWithSource(source, blankRenderer{delay: delay}, config)
+}
+
+func TestBackwardPlayback(t *testing.T) {
+	player := newBlankPlayer(3*time.Second, 10, 0)
+	defer player.Close()
+
+	player.SetBackward(true)
+	player.SeekTo(2 * time.Second)
+
+	var positions []time.Duration
+	for {
+		if _, err := player.GetNextFrame(); err != nil {
+			break
+		}
+		positions = append(positions, player.GetPosition())
+	}
+	if len(positions) != 20 {
+		t.Fatalf("got %d frames before 2s, want 20", len(positions))
+	}
+	for i := 1; i < len(positions); i++ {
+		if positions[i] >= positions[i-1] {
+			t.Fatalf("frame %d at %v does not precede %v", i, positions[i], positions[i-1])
+		}
+	}
+	if positions[0] >= 2*time.Second || positions[len(positions)-1] != 0 {
+		t.Errorf("played from %v to %v, want from before 2s down to 0", positions[0], positions[len(positions)-1])
+	}
+}
+
+func TestBackwardDecodeDoesNotBlockPosition(t *testing.T) {
+	// Every segment takes about 10 x 20ms to convert
+	player := newBlankPlayer(3*time.Second, 10, 20*time.Millisecond)
+	defer player.Close()
+
+	player.SetBackward(true)
+	player.SeekTo(2 * time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		player.GetNextFrame()
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	player.GetPosition()
+	player.GetCurrentFrame()
+	player.GetBufferedPosition()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("reading the position took %v while a segment was decoded", elapsed)
+	}
+	<-done
+}
+
+func TestSeekDuringBackwardDecode(t *testing.T) {
+	player := newBlankPlayer(3*time.Second, 10, 10*time.Millisecond)
+	defer player.Close()
+
+	player.SetBackward(true)
+	player.SeekTo(2 * time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		player.SeekTo(time.Second)
+		close(done)
+	}()
+	player.GetNextFrame()
+	<-done
+
+	if _, err := player.GetNextFrame(); err != nil {
+		t.Fatal(err)
+	}
+	if position := player.GetPosition(); position >= time.Second {
+		t.Errorf("first frame after seeking to 1s is at %v", position)
+	}
+}
@@ -0,0 +1,241 @@
+// Package visualizer draws the audio being played as a frequency spectrum or
+// an oscilloscope waveform. It also registers the "visualizer" render mode,
+// which plays the audio with the visualization in place of the video.
+package visualizer
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/kweonminsung/console-cinema/pkg/media"
+	"github.com/kweonminsung/console-cinema/pkg/types"
+	"gocv.io/x/gocv"
+)
+
+// ModeName is the --mode that shows the visualizer instead of the video
+const ModeName = "visualizer"
+
+func init() {
+	media.RegisterRenderer(ModeName, func(config types.PlayerConfig) (media.Renderer, error) {
+		return blankRenderer{}, nil
+	})
+}
+
+// blankRenderer turns every image into an empty frame. The player draws the
+// visualization over it when the frame is shown, since the audio it shows is
+// only known then.
+type blankRenderer struct{}
+
+func (blankRenderer) Convert(img gocv.Mat, width, height int, color bool) (*media.Frame, error) {
+	return media.NewFrame(width, height), nil
+}
+
+// Style is how the audio is drawn
+type Style string
+
+const (
+	// StyleSpectrum draws the loudness of each frequency band as a bar
+	StyleSpectrum Style = "spectrum"
+	// StyleWaveform draws the waveform like an oscilloscope
+	StyleWaveform Style = "waveform"
+)
+
+// Styles are all the visualizer styles
+var Styles = []Style{StyleSpectrum, StyleWaveform}
+
+// ParseStyle converts a string to a Style. An empty string means spectrum.
+func ParseStyle(s string) (Style, error) {
+	if s == "" {
+		return StyleSpectrum, nil
+	}
+	for _, style := range Styles {
+		if Style(s) == style {
+			return style, nil
+		}
+	}
+	return "", fmt.Errorf("unknown visualizer style %q (available: %v)", s, Styles)
+}
+
+const (
+	// WindowSize is the number of samples each drawing looks at, about 46 ms at 44.1 kHz
+	WindowSize = 2048
+
+	// The spectrum spans minFrequency to maxFrequency on a logarithmic scale
+	minFrequency = 40.0
+	maxFrequency = 16000.0
+	// floorDecibels is the level drawn as an empty bar
+	floorDecibels = -70.0
+	// barDecay is how much of its height a bar keeps per drawing when the level drops, so bars fall smoothly
+	barDecay = 0.85
+)
+
+// Colors of the spectrum bars from the bottom to the top, and of the waveform
+var (
+	lowColor      = [3]float64{0x2e, 0xcc, 0x71}
+	midColor      = [3]float64{0xf1, 0xc4, 0x0f}
+	highColor     = [3]float64{0xe7, 0x4c, 0x3c}
+	waveformColor = media.NewRGBColor(0x3d, 0xd6, 0xf5)
+)
+
+// Visualizer draws frames from windows of audio samples. It keeps the bar
+// heights between drawings, so it must not be used from several goroutines.
+type Visualizer struct {
+	style      Style
+	sampleRate int
+	color      bool
+
+	window   []float64
+	spectrum []complex128
+	bars     []float64
+}
+
+// New creates a Visualizer for audio at sampleRate. Without color the
+// drawing uses the default terminal colors.
+func New(style Style, sampleRate int, color bool) *Visualizer {
+	window := make([]float64, WindowSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(WindowSize))
+	}
+	return &Visualizer{
+		style:      style,
+		sampleRate: sampleRate,
+		color:      color,
+		window:     window,
+		spectrum:   make([]complex128, WindowSize),
+	}
+}
+
+// Style returns how the Visualizer draws
+func (v *Visualizer) Style() Style {
+	return v.style
+}
+
+// Draw draws the WindowSize samples ending now into a width x height frame
+func (v *Visualizer) Draw(samples [][2]float64, width, height int) *media.Frame {
+	frame := media.NewFrame(max(width, 0), max(height, 0))
+	if width <= 0 || height <= 0 {
+		return frame
+	}
+	if v.style == StyleWaveform {
+		v.drawWaveform(frame, samples)
+	} else {
+		v.drawSpectrum(frame, samples)
+	}
+	return frame
+}
+
+// drawSpectrum draws one bar per column for logarithmically spaced frequency
+// bands, with eighth blocks for the top of each bar
+func (v *Visualizer) drawSpectrum(frame *media.Frame, samples [][2]float64) {
+	for i := range v.spectrum {
+		var sample float64
+		if i < len(samples) {
+			sample = (samples[i][0] + samples[i][1]) / 2
+		}
+		v.spectrum[i] = complex(sample*v.window[i], 0)
+	}
+	fft(v.spectrum)
+
+	if len(v.bars) != frame.Width {
+		v.bars = make([]float64, frame.Width)
+	}
+	top := min(maxFrequency, float64(v.sampleRate)/2)
+	binWidth := float64(v.sampleRate) / WindowSize
+	for x := range v.bars {
+		low := minFrequency * math.Pow(top/minFrequency, float64(x)/float64(frame.Width))
+		high := minFrequency * math.Pow(top/minFrequency, float64(x+1)/float64(frame.Width))
+		first := int(low / binWidth)
+		last := max(first+1, int(math.Ceil(high/binWidth)))
+
+		var magnitude float64
+		for bin := first; bin < last && bin < WindowSize/2; bin++ {
+			magnitude = max(magnitude, cmplx.Abs(v.spectrum[bin]))
+		}
+		// A full scale sine peaks at WindowSize/4 with the Hann window
+		amplitude := magnitude * 4 / WindowSize
+		level := 0.0
+		if amplitude > 0 {
+			level = (20*math.Log10(amplitude) - floorDecibels) / -floorDecibels
+		}
+		level = max(0, min(level, 1))
+		v.bars[x] = max(level, v.bars[x]*barDecay)
+	}
+
+	for x, level := range v.bars {
+		eighths := int(level * float64(frame.Height*8))
+		for row := 0; row < frame.Height && eighths > 0; row++ {
+			y := frame.Height - 1 - row
+			r := '█'
+			if eighths < 8 {
+				r = '▁' + rune(eighths-1)
+			}
+			eighths -= 8
+
+			cell := media.Cell{Rune: r}
+			if v.color {
+				cell.Fg = barColor(float64(row) / float64(max(frame.Height-1, 1)))
+			}
+			frame.Set(x, y, cell)
+		}
+	}
+}
+
+// barColor blends from green at the bottom (0) through yellow to red at the top (1)
+func barColor(t float64) media.Color {
+	from, to := lowColor, midColor
+	if t > 0.5 {
+		from, to, t = midColor, highColor, t-0.5
+	}
+	t *= 2
+	return media.NewRGBColor(
+		uint8(from[0]+(to[0]-from[0])*t),
+		uint8(from[1]+(to[1]-from[1])*t),
+		uint8(from[2]+(to[2]-from[2])*t))
+}
+
+// drawWaveform draws the range of the samples under each column with half
+// blocks, which doubles the vertical resolution
+func (v *Visualizer) drawWaveform(frame *media.Frame, samples [][2]float64) {
+	rows := frame.Height * 2
+	// toRow maps a sample value in [-1, 1] to a half row, with 1 at the top
+	toRow := func(value float64) int {
+		value = max(-1, min(value, 1))
+		return int((1 - value) / 2 * float64(rows-1))
+	}
+
+	for x := 0; x < frame.Width; x++ {
+		first := x * len(samples) / frame.Width
+		last := max(first+1, (x+1)*len(samples)/frame.Width)
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, sample := range samples[first:min(last, len(samples))] {
+			mono := (sample[0] + sample[1]) / 2
+			low, high = min(low, mono), max(high, mono)
+		}
+		if math.IsInf(low, 0) {
+			low, high = 0, 0
+		}
+
+		top, bottom := toRow(high), toRow(low)
+		for y := 0; y < frame.Height; y++ {
+			upper := 2*y >= top && 2*y <= bottom
+			lower := 2*y+1 >= top && 2*y+1 <= bottom
+			var r rune
+			switch {
+			case upper && lower:
+				r = '█'
+			case upper:
+				r = '▀'
+			case lower:
+				r = '▄'
+			default:
+				continue
+			}
+			cell := media.Cell{Rune: r}
+			if v.color {
+				cell.Fg = waveformColor
+			}
+			frame.Set(x, y, cell)
+		}
+	}
+}